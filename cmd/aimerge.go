@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/ai"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/runs"
+	"os"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS AI DB Merge")
+
+	err := merge()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// merge - Merges legacy numbered AI DB text files into the consolidated AI DB of a node tree
+func merge() (err error) {
+	_, _, name, err := conf.GetPlayOptions()
+	if err != nil {
+		return
+	}
+	if err = runs.CheckTree(name); err != nil {
+		return
+	}
+
+	files, err := ai.LegacyFiles(name)
+	if err != nil {
+		return
+	}
+	if len(files) == 0 {
+		fmt.Printf("No legacy AI DB files found for %s\n", name)
+		return
+	}
+
+	aiMgmt, err := ai.NewAI(name, conf.AIHighValueThreshold, conf.AILowValueThreshold, conf.AIVisitsThreshold, conf.AIUniqueStates, conf.AIFlushSize, false)
+	if err != nil {
+		return
+	}
+	defer func() { _ = aiMgmt.WriteAndCloseBuffers() }()
+
+	fmt.Println("Legacy labels were learned with points credited to the player in turn and are inverted when merged")
+
+	// Files are merged in index order so that labels from later runs replace those from earlier
+	var n int
+	for _, file := range files {
+		n, err = aiMgmt.MergeLegacyFile(file)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Merged %d records from %s\n", n, file)
+	}
+
+	if err = aiMgmt.WriteAndCloseBuffers(); err != nil {
+		return
+	}
+
+	remove, err := conf.GetBool("Remove merged legacy files [false]: ")
+	if err != nil || !remove {
+		return
+	}
+	for _, file := range files {
+		if err = os.Remove(file); err != nil {
+			fmt.Printf("Error while removing file %s: %s\n", file, err)
+			return
+		}
+	}
+	fmt.Printf("Removed %d legacy files\n", len(files))
+
+	return
+}
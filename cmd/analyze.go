@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"strings"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Analyze")
+
+	err := analyze()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// analyze - Prints what the tree has learned about a position
+func analyze() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	position, err := conf.GetPosition()
+	if err != nil {
+		return
+	}
+	state, player, err := tree.ParsePosition(position)
+	if err != nil {
+		return
+	}
+
+	tree.Game.SetState(state, player)
+	tree.PrintBoard()
+	fmt.Printf("Position: %s\n", tree.Game.FormatPosition(state, player))
+
+	analysis, err := tree.Analyze(state, player)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("Player in turn: %s, visits: %d, hidden actions: %d\n\n", analysis.Player, analysis.Visits, analysis.NHidden)
+	fmt.Printf("%-6s %10s %8s %10s %s\n", "Move", "Visits", "Value", "UCT", "Solved")
+	for _, a := range analysis.Actions {
+		fmt.Printf("%-6s %10d %8.4f %10.4f %s\n", mcts.FormatMove(a.Action), a.Visits, a.Value, a.UCT, solvedText(a))
+	}
+	if analysis.NHidden > 0 {
+		fmt.Printf("%-6s %d actions not yet exposed to learning\n", "hidden", analysis.NHidden)
+	}
+
+	moves := make([]string, len(analysis.Variation))
+	for i, a := range analysis.Variation {
+		moves[i] = mcts.FormatMove(a)
+	}
+	fmt.Printf("\nPrincipal variation: %s\n", strings.Join(moves, " "))
+
+	return
+}
+
+// solvedText - Returns the proven result of an action, if solved, and whether it ends the game
+func solvedText(a mcts.ActionAnalysis) string {
+	switch {
+	case !a.Solved:
+		return ""
+	case a.Terminal:
+		return mcts.SolvedResult(a.SolvedValue) + ", game ends"
+	default:
+		return mcts.SolvedResult(a.SolvedValue)
+	}
+}
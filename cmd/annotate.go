@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"os"
+	"strings"
+	"time"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Annotate")
+
+	err := annotate()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// annotate - Annotates a game record or a move list with the tree judgement of every move
+func annotate() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	input, err := conf.GetString("Game record file or move list (e.g. C4 D3 pass): ", "")
+	if err != nil {
+		return
+	}
+	position, err := conf.GetPosition()
+	if err != nil {
+		return
+	}
+	output, err := conf.GetString("Output file, empty prints to console []: ", "")
+	if err != nil {
+		return
+	}
+
+	var record *mcts.GameRecord
+	if _, statErr := os.Stat(input); statErr == nil {
+		record, err = mcts.LoadGameRecord(input)
+	} else {
+		record, err = mcts.ParseGameRecord(strings.NewReader(input))
+	}
+	if err != nil {
+		return
+	}
+	if position != "" {
+		record.Position = position
+	}
+	if record.Date == "" {
+		record.Date = time.Now().Format("2006.01.02")
+	}
+
+	annotated, annotations, err := tree.Annotate(record)
+	if err != nil {
+		return
+	}
+
+	if output == "" {
+		fmt.Println()
+		err = annotated.Write(os.Stdout)
+	} else if err = annotated.Save(output); err == nil {
+		fmt.Printf("Annotated record saved to %s\n", output)
+	}
+	if err != nil {
+		return
+	}
+
+	printSummary(tree, annotations)
+
+	return
+}
+
+// printSummary - Prints number of inaccuracies and blunders and the average value drop of judged moves per player
+func printSummary(tree *mcts.Tree, annotations []mcts.Annotation) {
+	fmt.Println()
+	for _, player := range []string{tree.PlayerA, tree.PlayerB} {
+		var moves, judged, inaccuracies, blunders int
+		var drop float64
+		for _, a := range annotations {
+			if a.Player != player {
+				continue
+			}
+			moves++
+			if !a.Judged {
+				continue
+			}
+			judged++
+			drop += a.Drop
+			switch a.Mark {
+			case mcts.MarkInaccuracy:
+				inaccuracies++
+			case mcts.MarkBlunder:
+				blunders++
+			}
+		}
+		if judged > 0 {
+			drop /= float64(judged)
+		}
+
+		fmt.Printf("%s: %d moves, %d judged, %d inaccuracies, %d blunders, average value drop %.3f\n",
+			player, moves, judged, inaccuracies, blunders, drop)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Opening Book")
+
+	err := buildBook()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// buildBook - Builds an opening book from the most visited lines of a node tree
+func buildBook() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	options, err := conf.GetBookOptions(tree.Name)
+	if err != nil {
+		return
+	}
+
+	openingBook, err := tree.BuildBook(options.MinVisits, options.MaxDepth, options.Breadth)
+	if err != nil {
+		fmt.Printf("Error while building opening book: %s\n", err)
+		return
+	}
+
+	if err = openingBook.Save(options.Output); err != nil {
+		return
+	}
+	fmt.Printf("Saved opening book with %d positions to %s\n", openingBook.Len(), options.Output)
+
+	return
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences
+const (
+	clearScreen = "\033[H\033[2J"
+	bold        = "\033[1m"
+	dim         = "\033[2m"
+	green       = "\033[32m"
+	red         = "\033[31m"
+	reset       = "\033[0m"
+)
+
+// frame - A node on the path from where browsing started, visits is the visits of the action leading to the node
+// or the sum of visits of its actions if there is no such action
+type frame struct {
+	node   db.MCNode
+	move   string
+	visits uint64
+}
+
+// child - An exposed action of the current node with its resulting node
+type child struct {
+	action      db.Action
+	node        db.MCNode
+	value       float64
+	solved      bool
+	solvedValue float64
+}
+
+// browser - State of a browsing session
+type browser struct {
+	tree    *mcts.Tree
+	path    []frame
+	byValue bool
+	status  string
+}
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Browse")
+
+	err := browse()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// browse - Browses a node tree read only starting at the top node
+func browse() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	b := &browser{tree: tree}
+	if err = b.top(); err != nil {
+		return
+	}
+
+	for {
+		var children []child
+		if children, err = b.children(); err != nil {
+			return
+		}
+		b.render(children)
+
+		var input string
+		if input, err = conf.GetString("> ", ""); err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(input, " ")
+		b.status = ""
+		switch strings.ToLower(command) {
+		case "":
+		case "q":
+			return
+		case "u":
+			if len(b.path) > 1 {
+				b.path = b.path[:len(b.path)-1]
+			} else {
+				b.status = "Already at the first node"
+			}
+		case "t":
+			if err = b.top(); err != nil {
+				return
+			}
+		case "s":
+			b.byValue = !b.byValue
+		case "j":
+			b.jump(argument)
+		default:
+			n, convErr := strconv.Atoi(command)
+			if convErr != nil || n < 1 || n > len(children) {
+				b.status = fmt.Sprintf("Unknown command or child: %s", input)
+				continue
+			}
+			c := children[n-1]
+			b.path = append(b.path, frame{node: c.node, move: mcts.FormatMove(b.move(c.action)), visits: c.action.Visits})
+		}
+	}
+}
+
+// top - Restarts browsing at the top node
+func (B *browser) top() error {
+	action, err := B.tree.NodeDB.GetTopAction()
+	if err != nil {
+		return err
+	}
+	B.path = []frame{{node: action.ActionNode, visits: action.Visits}}
+
+	return nil
+}
+
+// jump - Restarts browsing at a position given in notation of the game or as a base 3 state and player
+func (B *browser) jump(position string) {
+	if strings.TrimSpace(position) == "" {
+		B.status = "Give a position to jump to, e.g. j " + B.tree.Game.FormatPosition(B.current().node.State, B.current().node.Player)
+		return
+	}
+
+	state, player, err := B.tree.ParsePosition(position)
+	if err != nil {
+		B.status = fmt.Sprintf("Malformed position: %s", err)
+		return
+	}
+
+	node, err := B.tree.NodeDB.GetNode(db.StateKey(state, player == B.tree.PlayerA))
+	if err != nil {
+		B.status = fmt.Sprintf("Position not found in the tree: %s", position)
+		return
+	}
+
+	var visits uint64
+	for _, a := range node.Actions {
+		visits += a.Visits
+	}
+	B.path = []frame{{node: node, visits: visits}}
+}
+
+// current - Returns the current frame
+func (B *browser) current() frame {
+	return B.path[len(B.path)-1]
+}
+
+// children - Returns the exposed actions of the current node sorted by visits or value, with their proven values
+// if solved walking at most conf.SolveNodeLimit nodes
+func (B *browser) children() (children []child, err error) {
+	node := B.current().node
+	solver := B.tree.NewSolver(conf.SolveNodeLimit)
+	for _, a := range node.Actions {
+		c := child{action: a}
+		if c.node, err = B.tree.NodeDB.GetNode(a.ActionNodeKey); err != nil {
+			return
+		}
+		if a.Visits > 0 {
+			c.value = float64(a.Points) / 2 / float64(a.Visits)
+		}
+		if c.solvedValue, c.solved, err = solver.SolveAction(a, node.Player); err != nil {
+			return
+		}
+		children = append(children, c)
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		if B.byValue && children[i].value != children[j].value {
+			return children[i].value > children[j].value
+		}
+		return children[i].action.Visits > children[j].action.Visits
+	})
+
+	return
+}
+
+// move - Returns the move of an action
+func (B *browser) move(a db.Action) mcts.Action {
+	return mcts.Action{X: a.X, Y: a.Y, Pass: a.Pass}
+}
+
+// render - Clears the screen and shows the current node, its children and available commands
+func (B *browser) render(children []child) {
+	f := B.current()
+	node := f.node
+
+	fmt.Print(clearScreen)
+	fmt.Printf("%sPosition:%s %s\n", bold, reset, B.tree.Game.FormatPosition(node.State, node.Player))
+
+	moves := make([]string, 0, len(B.path)-1)
+	for _, p := range B.path[1:] {
+		moves = append(moves, p.move)
+	}
+	fmt.Printf("%sPath:%s %s\n", bold, reset, strings.Join(moves, " "))
+
+	isDone, winner := B.tree.Game.SetState(node.State, node.Player)
+	B.tree.PrintBoard()
+
+	fmt.Printf("Player in turn: %s, visits: %d", node.Player, f.visits)
+	switch {
+	case isDone && winner == "":
+		fmt.Print(", game ended in a draw")
+	case isDone:
+		fmt.Printf(", game won by %s", winner)
+	case node.Actions == nil:
+		fmt.Print(", not expanded")
+	}
+	if node.NHiddenActions > 0 {
+		fmt.Printf(", hidden actions: %d", node.NHiddenActions)
+	}
+	fmt.Print("\n\n")
+
+	if len(children) > 0 {
+		fmt.Printf("%s%4s  %-6s %10s %8s  %s%s\n", bold, "#", "Move", "Visits", "Value", "Solved", reset)
+	}
+	for i, c := range children {
+		color := ""
+		switch {
+		case c.action.Visits == 0:
+			color = dim
+		case c.solved && c.solvedValue == 1:
+			color = green
+		case c.solved && c.solvedValue == 0:
+			color = red
+		}
+		solved := ""
+		if c.solved {
+			solved = mcts.SolvedResult(c.solvedValue)
+		}
+		if c.node.IsEnd {
+			solved += ", game ends"
+		}
+		fmt.Printf("%s%4d  %-6s %10d %8.4f  %s%s\n", color, i+1, mcts.FormatMove(B.move(c.action)), c.action.Visits, c.value, solved, reset)
+	}
+	if node.NHiddenActions > 0 {
+		fmt.Printf("%s%4s  %d actions not yet exposed to learning%s\n", dim, "", node.NHiddenActions, reset)
+	}
+
+	sortedBy := "visits"
+	if B.byValue {
+		sortedBy = "value"
+	}
+	fmt.Printf("\nSorted by %s. Commands: <#> descend, u up, t top, s toggle sort, j <position> jump, q quit\n", sortedBy)
+	if B.status != "" {
+		fmt.Printf("%s%s%s\n", red, B.status, reset)
+	}
+}
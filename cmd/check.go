@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"sort"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Check")
+
+	err := check()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// check - Checks the integrity of a node tree and optionally repairs problems found
+func check() (err error) {
+	gameId, size, name, err := conf.GetPlayOptions()
+	if err != nil {
+		return
+	}
+	options, err := conf.GetCheckOptions()
+	if err != nil {
+		return
+	}
+
+	game, _, err := mcts.NewBoardGame(gameId, size)
+	if err != nil {
+		return
+	}
+
+	tree, deferFunc, err := mcts.OpenCheckTree(game, name, options.Repair)
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	var nListed int
+	report, err := tree.Check(options.Repair, func(problem mcts.CheckProblem) {
+		if options.MaxProblems > 0 && nListed >= options.MaxProblems {
+			return
+		}
+		nListed++
+
+		repaired := ""
+		if problem.Repaired {
+			repaired = " (repaired)"
+		}
+		fmt.Printf("%-14s %s: %s%s\n", problem.Kind, problem.Position, problem.Detail, repaired)
+	})
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("\nNodes: %d, unexpanded: %d, end: %d, unreferenced: %d\n",
+		report.NNodes, report.NUnexpandedNodes, report.NEndNodes, report.NUnreferenced)
+	fmt.Printf("Actions: %d, hidden: %d\n", report.NActions, report.NHiddenActions)
+
+	if len(report.Problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+
+	kinds := make([]string, 0, len(report.Problems))
+	for kind := range report.Problems {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	fmt.Println("Problems found:")
+	for _, kind := range kinds {
+		fmt.Printf("  %-14s %d\n", kind, report.Problems[kind])
+	}
+	if options.Repair {
+		fmt.Printf("Repaired %d problems\n", report.NRepaired)
+	}
+
+	return
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"math/rand"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Export")
+
+	err := export()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// export - Exports nodes of a node tree as training data, optionally split in a training and a validation set
+func export() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	options, err := conf.GetExportOptions(tree.Name)
+	if err != nil {
+		return
+	}
+
+	names := []string{options.Output}
+	if options.ValidationFraction > 0 {
+		names = []string{fmt.Sprintf("%s-train", options.Output), fmt.Sprintf("%s-val", options.Output)}
+	}
+
+	writers := make([]mcts.ExportWriter, len(names))
+	counts := make([]int, len(names))
+	for i, name := range names {
+		if options.Format == mcts.JSONLExport {
+			name = fmt.Sprintf("%s.jsonl", name)
+		}
+		writers[i], err = mcts.NewExportWriter(options.Format, name, tree.Game)
+		if err != nil {
+			return
+		}
+		defer func(w mcts.ExportWriter) { _ = w.Close() }(writers[i])
+	}
+
+	filter := mcts.ExportFilter{
+		MinVisits:  options.MinVisits,
+		MinDepth:   options.MinDepth,
+		MaxDepth:   options.MaxDepth,
+		MinMarkers: options.MinMarkers,
+		MaxMarkers: options.MaxMarkers,
+	}
+
+	err = tree.ExportRecords(filter, func(record mcts.ExportRecord) error {
+		w := 0
+		if len(writers) > 1 && rand.Float64() < options.ValidationFraction {
+			w = 1
+		}
+		counts[w]++
+
+		return writers[w].Write(record)
+	})
+	if err != nil {
+		fmt.Printf("Error while exporting node tree: %s\n", err)
+		return
+	}
+
+	for i, name := range names {
+		fmt.Printf("Exported %d positions to %s\n", counts[i], name)
+	}
+
+	return
+}
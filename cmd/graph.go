@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"os"
+	"strings"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Graph")
+
+	err := graph()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// graph - Exports the node tree under a position as a graph in Graphviz DOT or JSON format
+func graph() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	options, err := conf.GetGraphOptions(tree.Name)
+	if err != nil {
+		return
+	}
+
+	// The top node is used for the start position since it holds the total visits
+	var state, player string
+	if strings.TrimSpace(options.Position) != "" {
+		if state, player, err = tree.ParsePosition(options.Position); err != nil {
+			return
+		}
+	}
+
+	treeGraph, err := tree.BuildGraph(state, player, options.MaxDepth, options.MinVisits)
+	if err != nil {
+		return
+	}
+
+	f, err := os.Create(options.Output)
+	if err != nil {
+		fmt.Printf("Error while creating %s, %s\n", options.Output, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	if err = mcts.WriteGraph(f, treeGraph, options.Format); err != nil {
+		fmt.Printf("Error while writing graph: %s\n", err)
+		return
+	}
+
+	fmt.Printf("Exported %d nodes and %d actions to %s\n", len(treeGraph.Nodes), len(treeGraph.Edges), options.Output)
+	if options.Format == mcts.DOTGraph {
+		fmt.Printf("Render with e.g. dot -Tsvg %s -o %s.svg\n", options.Output, strings.TrimSuffix(options.Output, ".dot"))
+	}
+
+	return
+}
@@ -58,6 +58,7 @@ Loop:
 		select {
 		case <-ctx.Done():
 			fmt.Println("Received interrupt signal, saving and stopping learn loop")
+			tree.StoppedBy = mcts.StopInterrupted
 			break Loop
 		default:
 			// do a piece of work
@@ -68,10 +69,18 @@ Loop:
 			if complete {
 				break Loop
 			}
+
+			// Evaluate strength against baseline players if it is time to
+			err = tree.EvaluateStrength()
+			if err != nil {
+				err = fmt.Errorf("error while evaluating strength")
+				return
+			}
 		}
 	}
 
 	fmt.Printf("\nNumber of nodes in final tree: %d\n", tree.NNodes)
+	fmt.Printf("Stop criterion: %s\n", tree.StoppedBy)
 
 	err = tree.SaveState()
 	if err != nil {
@@ -94,6 +103,7 @@ func learnIteration(tree *mcts.Tree) (complete bool, err error) {
 	var actions []db.Action
 	var isEnd bool
 	var winner string
+	var result float64
 
 	// Execute an MCTS Select to find node to exploit or explore
 	actions, err = tree.Select()
@@ -109,6 +119,7 @@ func learnIteration(tree *mcts.Tree) (complete bool, err error) {
 
 	// Play the game up to and including te selected node
 	isEnd, winner = tree.PlayAction(actions[len(actions)-1])
+	result = tree.WinnerResult(winner)
 
 	if !isEnd {
 		// Execute an MCTS Expand to add new nodes to explore, one of the new nodes is randomly chosen and returned
@@ -120,10 +131,11 @@ func learnIteration(tree *mcts.Tree) (complete bool, err error) {
 
 		// Play the expanded node in the game
 		isEnd, winner = tree.PlayAction(actions[len(actions)-1])
+		result = tree.WinnerResult(winner)
 
 		if !isEnd {
-			// Simulate the game to an end using any simulation policy
-			winner, err = tree.Simulate()
+			// Simulate the game to an end (or to a cutoff) using any simulation policy
+			result, err = tree.Simulate(actions)
 			if err != nil {
 				err = fmt.Errorf("error performing Simulate")
 				return
@@ -141,7 +153,7 @@ func learnIteration(tree *mcts.Tree) (complete bool, err error) {
 	}
 
 	// Update statistics in the game tree
-	err = tree.BackPropagation(actions, winner)
+	err = tree.BackPropagation(actions, result)
 	if err != nil {
 		err = fmt.Errorf("error while performing back propagation")
 		return
@@ -1,37 +1,68 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // main - Main function
 func main() {
 	fmt.Println("MCTS Play")
 
-	// Assemble all parts that conforms to an MCTS tree in learning mode
+	// An interrupt ends the game the same way as end of input, so that the game is recorded
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	play(ctx)
+}
+
+// play - Plays a game against the tree, the game is recorded however it ends
+func play(ctx context.Context) {
+	// Assemble all parts that conforms to an MCTS tree in play mode
 	tree, passAllowed, deferFunc, err := mcts.AssembleForPlay()
 	defer deferFunc()
+	if err != nil {
+		return
+	}
 
-	err = tree.ResetPlayPlayerB()
+	position, err := conf.GetPosition()
+	if err != nil {
+		return
+	}
+
+	if position == "" {
+		err = tree.ResetPlayPlayerB()
+	} else {
+		var state, player string
+		if state, player, err = tree.ParsePosition(position); err == nil {
+			err = tree.ResetPlayPosition(state, player, tree.PlayerB)
+		}
+	}
 	if err != nil {
 		fmt.Println("Unable to reset game")
 		return
 	}
+	defer saveRecord(tree)
 
-	reader := bufio.NewReader(os.Stdin)
+	lines := readLines()
 
 	fmt.Println("Ready to play!")
 	tree.PrintBoard()
 
 	var result mcts.MoveResult
 	for {
-		fmt.Print("Column [A,B...]: ")
-		moveX, _ := reader.ReadString('\n')
+		moveX, ok := nextLine(ctx, lines, "Column [A,B...]: ")
+		if !ok {
+			fmt.Println("\nGame ended before it was over")
+			return
+		}
 		moveX = strings.ToUpper(strings.TrimSpace(moveX))
 		x := strings.Index("ABCDEFGH", moveX)
 
@@ -42,8 +73,11 @@ func main() {
 				return
 			}
 		} else {
-			fmt.Print("Row [1,2...]: ")
-			moveY, _ := reader.ReadString('\n')
+			moveY, ok := nextLine(ctx, lines, "Row [1,2...]: ")
+			if !ok {
+				fmt.Println("\nGame ended before it was over")
+				return
+			}
 			y, _ := strconv.Atoi(strings.TrimSpace(moveY))
 
 			result, err = tree.PlayExploitPlayer(uint8(x), uint8(y)-1, false)
@@ -65,3 +99,44 @@ func main() {
 		}
 	}
 }
+
+// readLines - Returns a channel with lines read from the console, it is closed at end of input
+func readLines() <-chan string {
+	lines := make(chan string)
+	go func() {
+		for {
+			line, err := conf.GetString("", "")
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	return lines
+}
+
+// nextLine - Prints the prompt and returns the next line from the console, or false at end of input or when the
+// context is done
+func nextLine(ctx context.Context, lines <-chan string, prompt string) (line string, ok bool) {
+	fmt.Print(prompt)
+	select {
+	case line, ok = <-lines:
+		return
+	case <-ctx.Done():
+		return "", false
+	}
+}
+
+// saveRecord - Saves the record of the game if any move was made, an unfinished game is recorded with result *
+func saveRecord(tree *mcts.Tree) {
+	if tree.Record == nil || len(tree.Record.Moves) == 0 {
+		return
+	}
+
+	recordFile := fmt.Sprintf("%s-%s.rec", tree.Name, time.Now().Format("20060102-150405"))
+	if err := tree.Record.Save(recordFile); err == nil {
+		fmt.Printf("Game recorded in %s\n", recordFile)
+	}
+}
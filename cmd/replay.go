@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Replay")
+
+	err := replay()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// replay - Replays a recorded game printing the board and the tree evaluation of every move
+func replay() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	recordFile, err := conf.GetString("Game record file: ", "")
+	if err != nil {
+		return
+	}
+	record, err := mcts.LoadGameRecord(recordFile)
+	if err != nil {
+		return
+	}
+
+	state, player, err := tree.ParsePosition(record.Position)
+	if err != nil {
+		return
+	}
+	tree.Game.SetState(state, player)
+	tree.PrintBoard()
+
+	for i, m := range record.Moves {
+		state, player = tree.Game.GetState()
+
+		fmt.Printf("Ply %d: %s plays %s", i+1, player, mcts.FormatMove(m.Action))
+		if m.Comment != "" {
+			fmt.Printf(" {%s}", m.Comment)
+		}
+		fmt.Println()
+
+		var evaluation mcts.MoveEvaluation
+		if evaluation, err = tree.EvaluateMove(state, player, m.Action); err != nil {
+			return
+		}
+		printEvaluation(evaluation)
+
+		isDone, winner, moveErr := tree.Game.Move(m.Action.X, m.Action.Y, m.Action.Pass)
+		if moveErr != nil {
+			return fmt.Errorf("illegal move at ply %d: %s", i+1, moveErr)
+		}
+		tree.PrintBoard()
+
+		if isDone {
+			if winner != "" {
+				fmt.Printf("Winner is %s\n", winner)
+			} else {
+				fmt.Println("Game is a draw")
+			}
+			break
+		}
+	}
+	fmt.Printf("Recorded result: %s\n", record.Result)
+
+	return
+}
+
+// printEvaluation - Prints the tree evaluation of a played move
+func printEvaluation(evaluation mcts.MoveEvaluation) {
+	if !evaluation.InTree {
+		fmt.Println("Tree: position not in tree")
+		return
+	}
+
+	if evaluation.PlayedFound {
+		fmt.Printf("Tree: played visits=%d value=%.3f", evaluation.Played.Visits, evaluation.Played.Value)
+	} else {
+		fmt.Print("Tree: played move not in tree")
+	}
+	fmt.Printf(", best %s visits=%d value=%.3f\n", mcts.FormatMove(evaluation.Best.Action), evaluation.Best.Visits, evaluation.Best.Value)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/runs"
+	"os"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Resize")
+
+	err := resize()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// resize - Rehashes the node hash map of a stored node tree into a map sized for a new estimate of unique states
+func resize() (err error) {
+	gameId, size, name, err := conf.GetPlayOptions()
+	if err != nil {
+		return
+	}
+
+	game, _, err := mcts.NewBoardGame(gameId, size)
+	if err != nil {
+		return
+	}
+
+	// Resizing must never create a new node tree
+	if err = runs.CheckTree(name); err != nil {
+		return
+	}
+	if err = db.RecoverResize(name); err != nil {
+		return
+	}
+	mFile := fmt.Sprintf("%s-map.bin", name)
+	if _, err = os.Stat(mFile); err != nil {
+		fmt.Printf("Error, no node tree named %s\n", name)
+		return
+	}
+
+	players := game.GetPlayers()
+	initialState, _ := game.GetState()
+	nodeTree, err := db.NewPlayNodeTree(name, players[0], players[1], initialState)
+	if err != nil {
+		fmt.Println("Error while open file based node database")
+		return fmt.Errorf("error while open file based node database")
+	}
+
+	fmt.Println("Scanning node hash map...")
+	stats, err := nodeTree.HashMapStats()
+	if err != nil {
+		return
+	}
+	usage, err := nodeTree.HashMapUsage()
+	_ = nodeTree.ActionsFile.Close()
+	nodeTree.NodeMap.CloseFiles()
+	if err != nil {
+		return
+	}
+	printHashMapStats(stats)
+
+	uniqueStates, err := conf.GetResizeOptions(mcts.ResizedUniqueStates(usage, int64(stats.Records)))
+	if err != nil {
+		return
+	}
+	if uniqueStates < 1 {
+		fmt.Println("Error, unique states must be at least one")
+		return fmt.Errorf("error, unique states must be at least one")
+	}
+
+	fmt.Println("Rehashing node hash map...")
+	if err = db.ResizeHashMap(name, uniqueStates); err != nil {
+		return
+	}
+
+	header, err := db.ReadHashMapHeader(name)
+	if err != nil {
+		return
+	}
+	fmt.Printf("Done, %d buckets with %d records each\n", header.Buckets, header.RecordsPerBucket)
+
+	return
+}
+
+// printHashMapStats - Prints usage of a node hash map
+func printHashMapStats(stats db.HashMapStats) {
+	fmt.Printf("Buckets: %d, records per bucket: %d\n", stats.Buckets, stats.RecordsPerBucket)
+	fmt.Printf("Records: %d, in map file: %d, in overflow file: %d\n", stats.Records, stats.MapFileRecords, stats.OverflowRecords)
+	fmt.Printf("Load factor: %.3f\n", stats.LoadFactor)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/runs"
+	"path/filepath"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Runs")
+
+	err := manageRuns()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// manageRuns - Lists, deletes or copies runs in the data directory, or adopts node trees from a folder into runs
+func manageRuns() (err error) {
+	options, err := conf.GetRunsOptions()
+	if err != nil {
+		return
+	}
+
+	switch options.Command {
+	case 0:
+		return listRuns()
+	case 1:
+		if !options.Confirm {
+			fmt.Println("Nothing deleted")
+			return
+		}
+		if err = runs.Delete(options.Run); err != nil {
+			return
+		}
+		fmt.Printf("Deleted run %s\n", options.Run)
+	case 2:
+		if err = runs.Copy(options.Run, options.CopyTo); err != nil {
+			return
+		}
+		fmt.Printf("Copied run %s to %s\n", options.Run, options.CopyTo)
+	case 3:
+		return adoptTrees(options.Folder)
+	default:
+		fmt.Println("No command corresponding to given command number")
+		return fmt.Errorf("error, no command corresponding to given command number")
+	}
+
+	return
+}
+
+// listRuns - Prints the runs in the data directory
+func listRuns() (err error) {
+	runInfos, err := runs.List()
+	if err != nil {
+		return
+	}
+	if len(runInfos) == 0 {
+		fmt.Printf("No runs in %s\n", conf.DataDirectory())
+		return
+	}
+
+	fmt.Printf("Runs in %s\n", conf.DataDirectory())
+	fmt.Printf("%-24s %-20s %12s %10s  %-16s  %-16s\n", "Run", "Tree", "Rounds", "MB", "Created", "Updated")
+	for _, r := range runInfos {
+		fmt.Printf(
+			"%-24s %-20s %12.0f %10.1f  %-16s  %-16s\n",
+			r.Run,
+			r.Tree,
+			r.Rounds,
+			float64(r.Bytes)/1024/1024,
+			r.Created.Format("2006-01-02 15:04"),
+			r.Updated.Format("2006-01-02 15:04"),
+		)
+	}
+
+	return
+}
+
+// adoptTrees - Moves node trees found in a folder, e.g. trees learned before runs were introduced, into runs named
+// after the trees. Since the default run of a game is named after its tree, the trees are then found as before.
+func adoptTrees(folder string) (err error) {
+	trees, err := runs.FindTrees(folder)
+	if err != nil {
+		return
+	}
+	if len(trees) == 0 {
+		fmt.Printf("No node trees in %s\n", folder)
+		return
+	}
+
+	fmt.Printf("Node trees in %s\n", folder)
+	for _, tree := range trees {
+		fmt.Printf("%-20s %3d files\n", tree.Tree, len(tree.Files))
+	}
+
+	adopt, err := conf.GetBool(fmt.Sprintf("Move the node trees into runs in %s [false]: ", conf.DataDirectory()))
+	if err != nil || !adopt {
+		return
+	}
+
+	for _, tree := range trees {
+		if err = runs.Adopt(tree, tree.Tree); err != nil {
+			return
+		}
+		fmt.Printf("Adopted %s into run %s\n", tree.Tree, tree.Tree)
+
+		// Trees of an older format than the one written by this version must be relearned
+		format, formatErr := db.NodeTreeFormat(filepath.Join(conf.RunDir(tree.Tree), tree.Tree))
+		if formatErr == nil && format != db.TreeFormat {
+			fmt.Printf("Warning, %s is a node tree of format %d and can't be opened until relearned with force new, legacy AI DB files can be merged with aimerge\n", tree.Tree, format)
+		}
+	}
+
+	return
+}
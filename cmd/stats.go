@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"io"
+	"os"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Statistics")
+
+	err := stats()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// stats - Reports statistics over a stored node tree in text or JSON format
+func stats() (err error) {
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	options, err := conf.GetStatsOptions()
+	if err != nil {
+		return
+	}
+	if options.Format != 0 && options.Format != 1 {
+		fmt.Println("No statistics format corresponding to given format number")
+		return fmt.Errorf("error, no statistics format corresponding to given format number")
+	}
+
+	treeStats, err := tree.Statistics()
+	if err != nil {
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if options.Output != "" {
+		var f *os.File
+		if f, err = os.Create(options.Output); err != nil {
+			fmt.Printf("Error while creating %s, %s\n", options.Output, err)
+			return
+		}
+		defer func(f *os.File) { _ = f.Close() }(f)
+		out = f
+	}
+
+	if options.Format == 1 {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(treeStats)
+	} else {
+		err = writeText(out, treeStats)
+	}
+	if err != nil {
+		fmt.Printf("Error while writing statistics: %s\n", err)
+		return
+	}
+
+	if options.Output != "" {
+		fmt.Printf("Statistics written to %s\n", options.Output)
+	}
+
+	return
+}
+
+// writeText - Writes statistics as a human readable report
+func writeText(w io.Writer, s mcts.TreeStats) error {
+	p := func(format string, a ...any) {
+		_, _ = fmt.Fprintf(w, format, a...)
+	}
+
+	p("\nTree %s after %.0f rounds\n\n", s.Name, s.Rounds)
+	p("Nodes:               %d\n", s.Nodes)
+	p("  expanded:          %d\n", s.ExpandedNodes)
+	p("  unexpanded:        %d\n", s.UnexpandedNodes)
+	p("  end:               %d (%.2f%%)\n", s.EndNodes, 100*s.EndRatio)
+	p("  solved:            %d (%.2f%%)\n", s.SolvedNodes, 100*s.SolvedRatio)
+	p("  unreachable:       %d\n", s.UnreachableNodes)
+	p("Actions:             %d (%d hidden)\n", s.Actions, s.HiddenActions)
+	p("Branching factor:    %.2f average, %d max\n", s.AvgBranching, s.MaxBranching)
+	p("Transpositions:      %d nodes with several parents, %.2f%% of actions lead to them\n",
+		s.MultiParentNodes, 100*s.TranspositionRatio)
+	if s.RootSolved {
+		p("Top node:            solved with value %.1f\n", s.RootValue)
+	} else {
+		p("Top node:            not solved\n")
+	}
+
+	v := s.Visits
+	p("\nNode visits:         min %d, p25 %d, median %d, p75 %d, p90 %d, p99 %d, max %d, mean %.1f\n",
+		v.Min, v.P25, v.Median, v.P75, v.P90, v.P99, v.Max, v.Mean)
+
+	p("\n%6s %10s %10s %10s\n", "Depth", "Nodes", "Expanded", "End")
+	for _, d := range s.Depths {
+		p("%6d %10d %10d %10d\n", d.Depth, d.Nodes, d.Expanded, d.End)
+	}
+
+	h := s.HashMap
+	p("\nHash map:            %d buckets of %d records, load factor %.3f\n", h.Buckets, h.RecordsPerBucket, h.LoadFactor)
+	p("  records:           %d (%d in map file, %d in overflow file)\n", h.Records, h.MapFileRecords, h.OverflowRecords)
+
+	p("\nFiles:\n")
+	for _, f := range s.Files {
+		p("  %-40s %12d bytes\n", f.Name, f.Size)
+	}
+
+	return nil
+}
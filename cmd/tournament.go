@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Tournament")
+
+	err := tournament()
+	if err != nil {
+		fmt.Printf("Ended with error: %s\n", err)
+	}
+}
+
+// tournament - Plays every pair of players against each other and reports results and Elo ratings
+func tournament() (err error) {
+	gameId, size, name, err := conf.GetPlayOptions()
+	if err != nil {
+		return
+	}
+	options, err := conf.GetTournamentOptions()
+	if err != nil {
+		return
+	}
+
+	newGame := func() (mcts.BoardGame, error) {
+		game, _, gameErr := mcts.NewBoardGame(gameId, size)
+		return game, gameErr
+	}
+
+	// Stored trees are opened once each and shared by all games
+	trees := make(map[string]*mcts.Tree)
+	var deferFuncs []func()
+	defer func() {
+		for _, f := range deferFuncs {
+			f()
+		}
+	}()
+
+	players := make([]mcts.GamePlayer, 0, len(options.Players))
+	names := make([]string, 0, len(options.Players))
+	for _, spec := range options.Players {
+		var player mcts.GamePlayer
+		if player, err = newPlayer(spec, name, newGame, trees, &deferFuncs); err != nil {
+			return
+		}
+		for _, n := range names {
+			if n == player.Name() {
+				return fmt.Errorf("player %s is given more than once", n)
+			}
+		}
+		players = append(players, player)
+		names = append(names, player.Name())
+	}
+
+	results := make([]mcts.MatchResult, 0, len(players)*(len(players)-1)/2)
+	for i := 0; i < len(players); i++ {
+		for j := i + 1; j < len(players); j++ {
+			fmt.Printf("\nPlaying %s vs %s\n", players[i].Name(), players[j].Name())
+
+			var result mcts.MatchResult
+			if result, err = mcts.PlayMatch(newGame, [2]mcts.GamePlayer{players[i], players[j]}, options.Games, options.Concurrency); err != nil {
+				return
+			}
+			printMatchResult(result)
+			results = append(results, result)
+		}
+	}
+
+	printRatings(names, mcts.FitRatings(names, results))
+
+	return
+}
+
+// newPlayer - Returns a new player given its specification, see conf.TournamentOptions
+func newPlayer(
+	spec,
+	defaultTree string,
+	newGame func() (mcts.BoardGame, error),
+	trees map[string]*mcts.Tree,
+	deferFuncs *[]func(),
+) (mcts.GamePlayer, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+
+	switch kind {
+	case "exploit":
+		// Other runs hold a tree of the same game and size
+		treeName := defaultTree
+		if arg != "" {
+			if err := conf.CheckRunName(arg); err != nil {
+				return nil, err
+			}
+			treeName = filepath.Join(conf.RunDir(arg), filepath.Base(defaultTree))
+		}
+
+		tree, ok := trees[treeName]
+		if !ok {
+			game, err := newGame()
+			if err != nil {
+				return nil, err
+			}
+			var deferFunc func()
+			tree, deferFunc, err = mcts.OpenPlayTree(game, treeName)
+			*deferFuncs = append(*deferFuncs, deferFunc)
+			if err != nil {
+				return nil, err
+			}
+			trees[treeName] = tree
+		}
+
+		return mcts.NewExploitPlayer(tree), nil
+
+	case "mcts":
+		budget, err := strconv.Atoi(arg)
+		if err != nil || budget < 1 {
+			fmt.Printf("Error, malformed number of rounds for player %s\n", spec)
+			return nil, fmt.Errorf("error, malformed number of rounds for player %s", spec)
+		}
+
+		return mcts.NewOnlinePlayer(budget), nil
+
+	case "random":
+		return mcts.NewRandomPlayer(), nil
+
+	case "heuristic":
+		return mcts.NewHeuristicPlayer(), nil
+
+	default:
+		fmt.Printf("Error, unknown player: %s\n", spec)
+		return nil, fmt.Errorf("error, unknown player: %s", spec)
+	}
+}
+
+// printMatchResult - Prints wins, draws and losses of the first player with confidence intervals and Elo difference
+func printMatchResult(result mcts.MatchResult) {
+	fmt.Printf("%s vs %s, %d games\n", result.Players[0], result.Players[1], result.Games())
+
+	for _, o := range []struct {
+		label string
+		count int
+	}{{"Wins", result.Wins}, {"Draws", result.Draws}, {"Losses", result.Losses}} {
+		rate, low, high := result.RateInterval(o.count)
+		fmt.Printf("  %-7s %4d  %5.1f%% [%5.1f%%, %5.1f%%]\n", o.label, o.count, 100*rate, 100*low, 100*high)
+	}
+
+	scoreLow, scoreHigh := result.ScoreInterval()
+	elo, eloLow, eloHigh := result.Elo()
+	fmt.Printf("  Score   %.3f [%.3f, %.3f], Elo %s [%s, %s]\n",
+		result.Score(), scoreLow, scoreHigh, formatElo(elo), formatElo(eloLow), formatElo(eloHigh))
+}
+
+// printRatings - Prints ratings sorted from the strongest player
+func printRatings(names []string, ratings map[string]float64) {
+	sorted := append([]string{}, names...)
+	sort.SliceStable(sorted, func(i, j int) bool { return ratings[sorted[i]] > ratings[sorted[j]] })
+
+	fmt.Printf("\nElo ratings relative to %s\n", names[0])
+	for _, n := range sorted {
+		fmt.Printf("  %-30s %s\n", n, formatElo(ratings[n]))
+	}
+}
+
+// formatElo - Formats an Elo difference with sign, infinite differences as +inf and -inf
+func formatElo(elo float64) string {
+	if math.IsInf(elo, 1) {
+		return "+inf"
+	} else if math.IsInf(elo, -1) {
+		return "-inf"
+	}
+
+	return fmt.Sprintf("%+.0f", elo)
+}
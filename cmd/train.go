@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts"
+	"github.com/gostonefire/go-mcts-v3/internal/nn"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// main - Main function
+func main() {
+	fmt.Println("MCTS Train Network")
+
+	// Open the node tree read only, without AI DB and opening book
+	tree, deferFunc, err := mcts.AssembleForReading()
+	defer deferFunc()
+	if err != nil {
+		return
+	}
+
+	options, err := conf.GetTrainOptions()
+	if err != nil {
+		return
+	}
+
+	// Initial weights and shuffling of samples draw from a seeded generator so that training can be repeated
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	fmt.Printf("Random seed %d\n", seed)
+
+	// Continue training an existing network unless a new one is asked for
+	var network *nn.Network
+	networkFile := fmt.Sprintf("%s-nn.bin", tree.Name)
+	if _, err = os.Stat(networkFile); err == nil && !options.NewNetwork {
+		network, err = nn.Load(networkFile)
+		if err != nil {
+			return
+		}
+		if err = mcts.CheckGameNetwork(tree.Game, network); err != nil {
+			return
+		}
+		fmt.Printf("Continue training network with hidden layers %v\n", network.HiddenSizes())
+	} else {
+		network = mcts.NewGameNetwork(tree.Game, options.HiddenSizes, rng)
+		fmt.Printf("Training new network with hidden layers %v\n", network.HiddenSizes())
+	}
+
+	samples, err := tree.NetworkSamples(options.MinVisits)
+	if err != nil {
+		fmt.Printf("Error while collecting samples from node tree: %s\n", err)
+		return
+	}
+	fmt.Printf("Collected %d samples from node tree\n", len(samples))
+
+	for e := 1; e <= options.Epochs; e++ {
+		loss := network.Train(samples, 1, options.LearningRate, options.BatchSize, rng)
+		fmt.Printf("Epoch %d, loss %.4f\n", e, loss)
+	}
+
+	if err = network.Save(networkFile); err != nil {
+		return
+	}
+	fmt.Printf("Network saved to %s\n", networkFile)
+}
@@ -1,9 +1,69 @@
 package conf
 
 const OverlearnFactor float64 = 100
+const UCTConstant float64 = 10
 const RandomRoundThreshold float32 = 0.1
+const MASTTemperature float64 = 1
+
+// Progressive widening exposes WideningFactor * visits^WideningExponent children of a node, zero factor exposes all
+const WideningFactor float64 = 0
+const WideningExponent float64 = 0.5
+
+// PUCT selection, Dirichlet noise is mixed into root priors by DirichletEpsilon (zero gives no noise) and is drawn
+// anew every DirichletNoiseRounds rounds
+const PUCTConstant float64 = 1.5
+const PriorTemperature float64 = 0.2
+const DirichletAlpha float64 = 0.3
+const DirichletEpsilon float64 = 0.25
+const DirichletNoiseRounds int64 = 800
 
 const AIHighValueThreshold float32 = 0.7
 const AILowValueThreshold float32 = 0.3
 const AIVisitsThreshold uint64 = 5
 const AIWarmUpRounds float64 = 10000
+
+// AI DB hash map sizing and number of buffered player/state pairs before flushing to file
+const AIUniqueStates int64 = 100000
+const AIFlushSize int = 10000
+
+// Annotation of played moves, a value drop from the most visited action of at least InaccuracyValueDrop marks an
+// inaccuracy and of at least BlunderValueDrop a blunder. Played actions with fewer visits than AnnotateMinVisits
+// aren't judged since their values are too uncertain.
+const InaccuracyValueDrop float64 = 0.05
+const BlunderValueDrop float64 = 0.15
+const AnnotateMinVisits uint64 = 10
+
+// Number of concurrent games when evaluating strength of a learning tree
+const EvalConcurrency int = 4
+
+// Checking a node tree reports nodes whose visits differ from the sum of visits of their actions by more than
+// expected, with CheckVisitsTolerance of the node visits as margin for statistics seeded from an opening book
+const CheckVisitsTolerance float64 = 0.01
+
+// Stop conditions of learning other than max rounds are checked every StopCheckRounds rounds, except for proving
+// the root solved which walks the tree and is only done every SolvedCheckRounds rounds. The walk keeps every node
+// it visits in memory and gives up after SolvedCheckNodeLimit nodes, so larger trees aren't stopped as solved. The
+// root has converged when its most visited action is the same and its value within ConvergenceTolerance over the
+// given number of checks.
+const StopCheckRounds float64 = 1000
+const SolvedCheckRounds float64 = 100000
+const SolvedCheckNodeLimit int = 5000000
+const ConvergenceTolerance float64 = 0.005
+
+// Showing which actions are solved when inspecting a tree walks the subtrees under the actions, at most
+// SolveNodeLimit nodes are walked per shown position and nodes beyond the limit count as unsolved
+const SolveNodeLimit int = 1000000
+
+// The node hash map is rehashed into a map with ResizeGrowthFactor times the buckets when records per record slot
+// exceed ResizeLoadFactor or the share of records in the overflow file exceeds ResizeOverflowShare, which is above
+// what a uniform hash gives at that load factor so that it only catches skewed distributions. The default
+// estimate of unique states is counted per game (see DefaultUniqueStates) and capped at MaxDefaultUniqueStates,
+// which keeps new trees small and leaves larger ones to grow by resizing.
+const ResizeLoadFactor float64 = 0.75
+const ResizeOverflowShare float64 = 0.2
+const ResizeGrowthFactor int64 = 2
+const MaxDefaultUniqueStates int64 = 1000000
+
+// Each run is kept in a folder of its own in DataDir, which the environment variable named DataDirEnv overrides
+const DataDir string = "runs"
+const DataDirEnv string = "MCTS_DATA_DIR"
@@ -3,105 +3,624 @@ package conf
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
+// stdinReader - Shared reader of console input, so several sets of options can be read after each other
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// Selection policies that can be chosen for learning, declared here since the mcts package depends on conf
+const (
+	UCTSelection int = iota
+	PUCTSelection
+)
+
+// Leaf evaluators of states at the playout cutoff that can be chosen for learning
+const (
+	HeuristicLeafEvaluator int = iota
+	NetworkLeafEvaluator
+)
+
+// LearnOptions - Options given by the executor for learning mode
+type LearnOptions struct {
+	GameId        int
+	Size          uint8
+	MaxRounds     float64
+	RoundsMode    int
+	Seed          int64
+	TimeBudget    int
+	NodeBudget    int64
+	DiskBudget    int64
+	StableChecks  int
+	StopIfSolved  bool
+	UniqueStates  int64
+	PlayoutPolicy int
+	PlayoutCutoff int
+	LeafEvaluator int
+	Selection     int
+	PriorModel    int
+	ForceNew      bool
+	SeedBook      bool
+	EvaluateEvery int
+	EvalGames     int
+	Run           string
+	Name          string
+}
+
 // GetLearnOptions - Gets input from the executor
-func GetLearnOptions() (gameId int, size uint8, maxRounds float64, uniqueStates int64, forceNew bool, name string, err error) {
-	var input string
-	var s, m, u int
-	size = 4
-	maxRounds = 1000000
-	uniqueStates = 100000000
+func GetLearnOptions() (options LearnOptions, err error) {
+	var n int
+	options = LearnOptions{
+		Size:      4,
+		MaxRounds: 1000000,
+	}
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 
-	fmt.Print("Game [0 - TicTacToe, 1 - Othello, 2 - V Four in a Row]: ")
-	input, err = reader.ReadString('\n')
-	if err != nil {
-		fmt.Printf("Error while reading input from console: %s\n", err)
+	if options.GameId, err = readInt(reader, "Game [0 - TicTacToe, 1 - Othello, 2 - V Four in a Row]: ", 0); err != nil {
 		return
 	}
-	if input = strings.TrimSpace(input); input != "" {
-		s, err = strconv.Atoi(strings.TrimSpace(input))
-		if err != nil {
-			fmt.Printf("Error, malformed number give: %s\n", err)
+
+	if options.GameId != 2 {
+		if n, err = readInt(reader, "Size [4]: ", int(options.Size)); err != nil {
 			return
 		}
-		gameId = s
+		options.Size = uint8(n)
 	}
 
-	if gameId != 2 {
-		fmt.Print("Size [4]: ")
-		input, err = reader.ReadString('\n')
-		if err != nil {
-			fmt.Printf("Error while reading input from console: %s\n", err)
+	if options.Run, err = readRun(reader, TreeName(options.GameId, options.Size)); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Max learning rounds [1000000]: ", int(options.MaxRounds)); err != nil {
+		return
+	}
+	options.MaxRounds = float64(n)
+
+	if options.RoundsMode, err = readInt(reader, "Rounds mode [0 - Continue for N more rounds, 1 - Run until N rounds in total]: ", 0); err != nil {
+		return
+	}
+
+	if options.TimeBudget, err = readInt(reader, "Time budget in minutes, 0 disables [0]: ", 0); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Node budget, 0 disables [0]: ", 0); err != nil {
+		return
+	}
+	options.NodeBudget = int64(n)
+
+	if n, err = readInt(reader, "Disk budget in MB for actions and overflow files, 0 disables [0]: ", 0); err != nil {
+		return
+	}
+	options.DiskBudget = int64(n)
+
+	if options.StableChecks, err = readInt(reader, "Stop when the root is stable over N checks, 0 disables [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.StopIfSolved, err = readBool(reader, fmt.Sprintf("Stop when the root is solved, walks the tree in memory every %.0f rounds [false]: ", SolvedCheckRounds)); err != nil {
+		return
+	}
+
+	options.UniqueStates = DefaultUniqueStates(options.GameId, options.Size)
+	if n, err = readInt(reader, fmt.Sprintf("Estimated unique states [%d]: ", options.UniqueStates), int(options.UniqueStates)); err != nil {
+		return
+	}
+	options.UniqueStates = int64(n)
+
+	if options.PlayoutPolicy, err = readInt(reader, "Playout policy [0 - Random, 1 - MAST, 2 - LGRF-2]: ", 0); err != nil {
+		return
+	}
+
+	if options.PlayoutCutoff, err = readInt(reader, "Playout cutoff in plies, 0 plays to the end [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.PlayoutCutoff > 0 {
+		if options.LeafEvaluator, err = readInt(reader, "Leaf evaluator [0 - Game heuristic, 1 - Network]: ", 0); err != nil {
 			return
 		}
-		if input = strings.TrimSpace(input); input != "" {
-			s, err = strconv.Atoi(strings.TrimSpace(input))
-			if err != nil {
-				fmt.Printf("Error, malformed number give: %s\n", err)
-				return
-			}
-			size = uint8(s)
+	}
+
+	if options.Selection, err = readInt(reader, "Selection [0 - UCT, 1 - PUCT]: ", 0); err != nil {
+		return
+	}
+
+	// PUCT selection takes priors from a model
+	if options.Selection == PUCTSelection {
+		if options.PriorModel, err = readInt(reader, "Prior model [0 - Uniform, 1 - Heuristic, 2 - AI DB, 3 - Network]: ", 0); err != nil {
+			return
+		}
+	}
+
+	if options.ForceNew, err = readBool(reader, "Force new tree [false]: "); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Random seed of a new tree, 0 seeds from the clock [0]: ", 0); err != nil {
+		return
+	}
+	options.Seed = int64(n)
+
+	if options.SeedBook, err = readBool(reader, "Seed a new tree from opening book [false]: "); err != nil {
+		return
+	}
+
+	if options.EvaluateEvery, err = readInt(reader, "Evaluate strength every N rounds, 0 disables [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.EvaluateEvery > 0 {
+		if options.EvalGames, err = readInt(reader, "Evaluation games per baseline player [20]: ", 20); err != nil {
+			return
+		}
+	}
+
+	options.Name = filepath.Join(RunDir(options.Run), TreeName(options.GameId, options.Size))
+
+	return
+}
+
+// DefaultUniqueStates - Returns the default estimate of unique states of a game and board size. It is the number of
+// positions having as many markers of each player as the rules of the game allow, whether or not a game would have
+// ended before reaching them, capped at MaxDefaultUniqueStates.
+func DefaultUniqueStates(gameId int, size uint8) int64 {
+	cells := int(size) * int(size)
+
+	var states float64
+	switch gameId {
+	case 0:
+		// Tic-tac-toe, the player starting has as many markers as the other player or one more
+		for markers := 0; markers <= cells; markers++ {
+			first := (markers + 1) / 2
+			states += binomial(cells, first) * binomial(cells-first, markers-first)
 		}
+	case 1:
+		// Othello, the four centre cells are never empty and any other cell is empty or has a marker of either player
+		states = math.Pow(2, 4) * math.Pow(3, float64(cells-4))
+	case 2:
+		// Vertical four in a row, each of the seven columns is filled from the bottom up to a height of six
+		states = math.Pow(math.Pow(2, 7)-1, 7)
+	default:
+		states = math.Pow(3, float64(cells))
+	}
+
+	return int64(math.Min(states, float64(MaxDefaultUniqueStates)))
+}
+
+// binomial - Returns the number of ways to choose k of n
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result *= float64(n-k+i) / float64(i)
 	}
 
-	fmt.Print("Max learning rounds [1000000]: ")
+	return math.Round(result)
+}
+
+// TrainOptions - Options given by the executor for training a network
+type TrainOptions struct {
+	HiddenSizes  []int
+	Epochs       int
+	LearningRate float64
+	BatchSize    int
+	MinVisits    uint64
+	NewNetwork   bool
+	Seed         int64
+}
+
+// GetTrainOptions - Gets input from the executor
+func GetTrainOptions() (options TrainOptions, err error) {
+	var n int
+	var input string
+	options = TrainOptions{
+		HiddenSizes:  []int{64, 64},
+		Epochs:       10,
+		LearningRate: 0.01,
+		BatchSize:    32,
+		MinVisits:    10,
+	}
+
+	reader := stdinReader
+
+	fmt.Print("Hidden layer sizes, comma separated [64,64]: ")
 	input, err = reader.ReadString('\n')
 	if err != nil {
 		fmt.Printf("Error while reading input from console: %s\n", err)
 		return
 	}
 	if input = strings.TrimSpace(input); input != "" {
-		m, err = strconv.Atoi(strings.TrimSpace(input))
-		if err != nil {
-			fmt.Printf("Error, malformed number give: %s\n", err)
-			return
+		options.HiddenSizes = options.HiddenSizes[:0]
+		for _, h := range strings.Split(input, ",") {
+			n, err = strconv.Atoi(strings.TrimSpace(h))
+			if err != nil {
+				fmt.Printf("Error, malformed number given: %s\n", err)
+				return
+			}
+			options.HiddenSizes = append(options.HiddenSizes, n)
 		}
-		maxRounds = float64(m)
 	}
 
-	fmt.Print("Estimated unique states [100000000]: ")
+	if options.Epochs, err = readInt(reader, "Epochs [10]: ", options.Epochs); err != nil {
+		return
+	}
+
+	fmt.Print("Learning rate [0.01]: ")
 	input, err = reader.ReadString('\n')
 	if err != nil {
 		fmt.Printf("Error while reading input from console: %s\n", err)
 		return
 	}
 	if input = strings.TrimSpace(input); input != "" {
-		u, err = strconv.Atoi(strings.TrimSpace(input))
+		options.LearningRate, err = strconv.ParseFloat(input, 64)
 		if err != nil {
-			fmt.Printf("Error, malformed number give: %s\n", err)
+			fmt.Printf("Error, malformed number given: %s\n", err)
 			return
 		}
-		uniqueStates = int64(u)
 	}
 
-	fmt.Print("Force new tree [false]: ")
-	input, err = reader.ReadString('\n')
+	if options.BatchSize, err = readInt(reader, "Batch size [32]: ", options.BatchSize); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Min visits for a position to be used [10]: ", int(options.MinVisits)); err != nil {
+		return
+	}
+	options.MinVisits = uint64(n)
+
+	if options.NewNetwork, err = readBool(reader, "Force new network [false]: "); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Random seed of initial weights and shuffling, 0 seeds from the clock [0]: ", 0); err != nil {
+		return
+	}
+	options.Seed = int64(n)
+
+	return
+}
+
+// ExportOptions - Options given by the executor for exporting the node tree as training data
+type ExportOptions struct {
+	MinVisits          uint64
+	MinDepth           int
+	MaxDepth           int
+	MinMarkers         int
+	MaxMarkers         int
+	Format             int
+	ValidationFraction float64
+	Output             string
+}
+
+// GetExportOptions - Gets input from the executor
+func GetExportOptions(name string) (options ExportOptions, err error) {
+	var n int
+	options = ExportOptions{
+		MinVisits: 10,
+		Output:    fmt.Sprintf("%s-export", name),
+	}
+
+	reader := stdinReader
+
+	if n, err = readInt(reader, "Min visits for a position to be exported [10]: ", int(options.MinVisits)); err != nil {
+		return
+	}
+	options.MinVisits = uint64(n)
+
+	if options.MinDepth, err = readInt(reader, "Min depth, top node is at depth 1 [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.MaxDepth, err = readInt(reader, "Max depth, 0 gives no limit [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.MinMarkers, err = readInt(reader, "Game phase, min markers on board [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.MaxMarkers, err = readInt(reader, "Game phase, max markers on board, 0 gives no limit [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.Format, err = readInt(reader, "Format [0 - JSONL, 1 - NPY]: ", 0); err != nil {
+		return
+	}
+
+	if options.ValidationFraction, err = readFloat(reader, "Validation fraction [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.Output, err = readString(reader, fmt.Sprintf("Output file prefix [%s]: ", options.Output), options.Output); err != nil {
+		return
+	}
+
+	return
+}
+
+// BookOptions - Options given by the executor for building an opening book
+type BookOptions struct {
+	MinVisits uint64
+	MaxDepth  int
+	Breadth   int
+	Output    string
+}
+
+// GetBookOptions - Gets input from the executor
+func GetBookOptions(name string) (options BookOptions, err error) {
+	var n int
+	options = BookOptions{
+		MinVisits: 100,
+		MaxDepth:  8,
+		Breadth:   3,
+		Output:    fmt.Sprintf("%s.book", name),
+	}
+
+	reader := stdinReader
+
+	if n, err = readInt(reader, "Min visits for a move to be in book [100]: ", int(options.MinVisits)); err != nil {
+		return
+	}
+	options.MinVisits = uint64(n)
+
+	if options.MaxDepth, err = readInt(reader, "Max depth in plies, 0 gives no limit [8]: ", options.MaxDepth); err != nil {
+		return
+	}
+
+	if options.Breadth, err = readInt(reader, "Max moves per position, 0 gives no limit [3]: ", options.Breadth); err != nil {
+		return
+	}
+
+	if options.Output, err = readString(reader, fmt.Sprintf("Output file [%s]: ", options.Output), options.Output); err != nil {
+		return
+	}
+
+	return
+}
+
+// DumpOptions - Options given by the executor for dumping nodes of the node tree
+type DumpOptions struct {
+	MinDepth int
+	MaxDepth int
+	Position string
+	MaxNodes int
+	Format   int
+	Output   string
+}
+
+// GetDumpOptions - Gets input from the executor
+func GetDumpOptions() (options DumpOptions, err error) {
+	reader := stdinReader
+
+	if options.MinDepth, err = readInt(reader, "Min depth, top node is at depth 1 [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.MaxDepth, err = readInt(reader, "Max depth, 0 gives no limit [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.Position, err = readString(reader, "Position, notation or base 3 state and player, empty dumps all nodes []: ", ""); err != nil {
+		return
+	}
+
+	if options.MaxNodes, err = readInt(reader, "Max number of nodes, 0 gives no limit [0]: ", 0); err != nil {
+		return
+	}
+
+	if options.Format, err = readInt(reader, "Format [0 - Text, 1 - JSON]: ", 0); err != nil {
+		return
+	}
+
+	if options.Output, err = readString(reader, "Output file, empty prints to console []: ", ""); err != nil {
+		return
+	}
+
+	return
+}
+
+// GraphOptions - Options given by the executor for exporting the node tree under a position as a graph
+type GraphOptions struct {
+	Position  string
+	MaxDepth  int
+	MinVisits uint64
+	Format    int
+	Output    string
+}
+
+// GetGraphOptions - Gets input from the executor
+func GetGraphOptions(name string) (options GraphOptions, err error) {
+	var n int
+	options = GraphOptions{
+		MaxDepth:  2,
+		MinVisits: 10,
+	}
+
+	reader := stdinReader
+
+	if options.Position, err = readString(reader, "Position, notation or base 3 state and player [start position]: ", ""); err != nil {
+		return
+	}
+
+	if options.MaxDepth, err = readInt(reader, "Max depth in plies below the position [2]: ", options.MaxDepth); err != nil {
+		return
+	}
+
+	if n, err = readInt(reader, "Min visits for an action to be followed [10]: ", int(options.MinVisits)); err != nil {
+		return
+	}
+	options.MinVisits = uint64(n)
+
+	if options.Format, err = readInt(reader, "Format [0 - DOT, 1 - JSON]: ", 0); err != nil {
+		return
+	}
+
+	extension := "dot"
+	if options.Format == 1 {
+		extension = "json"
+	}
+	options.Output = fmt.Sprintf("%s-graph.%s", name, extension)
+	if options.Output, err = readString(reader, fmt.Sprintf("Output file [%s]: ", options.Output), options.Output); err != nil {
+		return
+	}
+
+	return
+}
+
+// StatsOptions - Options given by the executor for reporting node tree statistics
+type StatsOptions struct {
+	Format int
+	Output string
+}
+
+// GetStatsOptions - Gets input from the executor
+func GetStatsOptions() (options StatsOptions, err error) {
+	reader := stdinReader
+
+	if options.Format, err = readInt(reader, "Format [0 - Text, 1 - JSON]: ", 0); err != nil {
+		return
+	}
+
+	if options.Output, err = readString(reader, "Output file, empty prints to console []: ", ""); err != nil {
+		return
+	}
+
+	return
+}
+
+// CheckOptions - Options given by the executor for checking a node tree
+type CheckOptions struct {
+	Repair      bool
+	MaxProblems int
+}
+
+// GetCheckOptions - Gets input from the executor
+func GetCheckOptions() (options CheckOptions, err error) {
+	options = CheckOptions{
+		MaxProblems: 50,
+	}
+
+	reader := stdinReader
+
+	if options.Repair, err = readBool(reader, "Repair problems found [false]: "); err != nil {
+		return
+	}
+
+	if options.MaxProblems, err = readInt(reader, "Max problems to list, 0 lists all [50]: ", options.MaxProblems); err != nil {
+		return
+	}
+
+	return
+}
+
+// GetResizeOptions - Gets the new estimate of unique states of a node tree from the executor given a suggestion
+func GetResizeOptions(suggested int64) (uniqueStates int64, err error) {
+	n, err := readInt(stdinReader, fmt.Sprintf("New estimated unique states [%d]: ", suggested), int(suggested))
 	if err != nil {
-		fmt.Printf("Error while reading input from console: %s\n", err)
 		return
 	}
-	if input = strings.ToUpper(strings.TrimSpace(input)); input == "TRUE" {
-		forceNew = true
+
+	return int64(n), nil
+}
+
+// RunsOptions - Options given by the executor for managing runs, the command is 0 to list, 1 to delete, 2 to copy
+// and 3 to adopt node trees from a folder into runs
+type RunsOptions struct {
+	Command int
+	Run     string
+	CopyTo  string
+	Folder  string
+	Confirm bool
+}
+
+// GetRunsOptions - Gets input from the executor
+func GetRunsOptions() (options RunsOptions, err error) {
+	reader := stdinReader
+
+	if options.Command, err = readInt(reader, "Command [0 - List, 1 - Delete, 2 - Copy, 3 - Adopt]: ", 0); err != nil {
+		return
+	}
+
+	switch options.Command {
+	case 1:
+		if options.Run, err = GetRunName("Run to delete: "); err != nil {
+			return
+		}
+		options.Confirm, err = readBool(reader, fmt.Sprintf("Delete run %s and all its files [false]: ", options.Run))
+	case 2:
+		if options.Run, err = GetRunName("Run to copy: "); err != nil {
+			return
+		}
+		options.CopyTo, err = GetRunName("New run: ")
+	case 3:
+		options.Folder, err = readString(reader, "Folder to adopt node trees from [.]: ", ".")
+	}
+
+	return
+}
+
+// TournamentOptions - Options given by the executor for a tournament. Players are given as exploit (the tree of the
+// given run, or exploit:<run> for the tree of another run), mcts:<rounds per move>, random or heuristic.
+type TournamentOptions struct {
+	Players     []string
+	Games       int
+	Concurrency int
+}
+
+// GetTournamentOptions - Gets input from the executor
+func GetTournamentOptions() (options TournamentOptions, err error) {
+	options = TournamentOptions{
+		Games:       100,
+		Concurrency: 4,
+	}
+
+	reader := stdinReader
+
+	var input string
+	prompt := "Players, comma separated (exploit[:run], mcts:<rounds>, random, heuristic) [exploit,random]: "
+	if input, err = readString(reader, prompt, "exploit,random"); err != nil {
+		return
+	}
+	for _, p := range strings.Split(input, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			options.Players = append(options.Players, p)
+		}
+	}
+	if len(options.Players) < 2 {
+		fmt.Println("Error, at least two players are needed")
+		err = fmt.Errorf("error, at least two players are needed")
+		return
 	}
 
-	name = fmt.Sprintf("nodetree%dx%d-%d", size, size, gameId)
+	if options.Games, err = readInt(reader, "Games per pair of players [100]: ", options.Games); err != nil {
+		return
+	}
+
+	if options.Concurrency, err = readInt(reader, "Concurrent games [4]: ", options.Concurrency); err != nil {
+		return
+	}
 
 	return
 }
 
+// GetPosition - Gets a position from the executor, either in the notation of the game or as a base 3 state followed
+// by the player in turn. Empty input is returned as is and is meant to give the start position.
+func GetPosition() (position string, err error) {
+	return readString(stdinReader, "Position, notation or base 3 state and player [start position]: ", "")
+}
+
 // GetPlayOptions - Gets input from the executor
 func GetPlayOptions() (gameId int, size uint8, name string, err error) {
 	var input string
 	var s int
 	size = 4
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 
 	fmt.Print("Game [0 - TicTacToe, 1 - Othello, 2 - V Four in a Row]: ")
 	input, err = reader.ReadString('\n')
@@ -135,7 +654,137 @@ func GetPlayOptions() (gameId int, size uint8, name string, err error) {
 		}
 	}
 
-	name = fmt.Sprintf("nodetree%dx%d-%d", size, size, gameId)
+	run, err := readRun(reader, TreeName(gameId, size))
+	if err != nil {
+		return
+	}
+	name = filepath.Join(RunDir(run), TreeName(gameId, size))
 
 	return
 }
+
+// DataDirectory - Returns the directory holding the folders of all runs
+func DataDirectory() string {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return dir
+	}
+
+	return DataDir
+}
+
+// RunDir - Returns the folder of the named run
+func RunDir(run string) string {
+	return filepath.Join(DataDirectory(), run)
+}
+
+// TreeName - Returns the name of the node tree of a game and board size, which prefixes the names of all files of
+// the tree in the folder of a run
+func TreeName(gameId int, size uint8) string {
+	return fmt.Sprintf("nodetree%dx%d-%d", size, size, gameId)
+}
+
+// readRun - Prompts for and reads the name of a run from the console, an empty input gives the default name
+func readRun(reader *bufio.Reader, defaultRun string) (run string, err error) {
+	if run, err = readString(reader, fmt.Sprintf("Run [%s]: ", defaultRun), defaultRun); err != nil {
+		return
+	}
+
+	return run, CheckRunName(run)
+}
+
+// CheckRunName - Checks that a run name can be used as the name of a folder in the data directory, names starting
+// with a dot are reserved for temporary folders
+func CheckRunName(run string) error {
+	if run == "" || strings.HasPrefix(run, ".") || strings.ContainsAny(run, `/\`) {
+		fmt.Printf("Error, malformed run name: %s\n", run)
+		return fmt.Errorf("error, malformed run name: %s", run)
+	}
+
+	return nil
+}
+
+// GetRunName - Prompts for and reads the name of a run from the console, there is no default
+func GetRunName(prompt string) (run string, err error) {
+	if run, err = readString(stdinReader, prompt, ""); err != nil {
+		return
+	}
+
+	return run, CheckRunName(run)
+}
+
+// readInt - Prompts for and reads an integer from the console, an empty input gives the default value
+func readInt(reader *bufio.Reader, prompt string, defaultValue int) (value int, err error) {
+	fmt.Print(prompt)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Printf("Error while reading input from console: %s\n", err)
+		return
+	}
+	if input = strings.TrimSpace(input); input == "" {
+		return defaultValue, nil
+	}
+
+	value, err = strconv.Atoi(input)
+	if err != nil {
+		fmt.Printf("Error, malformed number given: %s\n", err)
+	}
+
+	return
+}
+
+// readBool - Prompts for and reads a boolean from the console, only "true" (in any case) gives true
+func readBool(reader *bufio.Reader, prompt string) (value bool, err error) {
+	fmt.Print(prompt)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Printf("Error while reading input from console: %s\n", err)
+		return
+	}
+
+	return strings.ToUpper(strings.TrimSpace(input)) == "TRUE", nil
+}
+
+// GetBool - Prompts for and reads a boolean (true/false) from the console, anything but true gives false
+func GetBool(prompt string) (bool, error) {
+	return readBool(stdinReader, prompt)
+}
+
+// readFloat - Prompts for and reads a floating point number from the console, an empty input gives the default value
+func readFloat(reader *bufio.Reader, prompt string, defaultValue float64) (value float64, err error) {
+	fmt.Print(prompt)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Printf("Error while reading input from console: %s\n", err)
+		return
+	}
+	if input = strings.TrimSpace(input); input == "" {
+		return defaultValue, nil
+	}
+
+	value, err = strconv.ParseFloat(input, 64)
+	if err != nil {
+		fmt.Printf("Error, malformed number given: %s\n", err)
+	}
+
+	return
+}
+
+// readString - Prompts for and reads a string from the console, an empty input gives the default value
+func readString(reader *bufio.Reader, prompt string, defaultValue string) (value string, err error) {
+	fmt.Print(prompt)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Printf("Error while reading input from console: %s\n", err)
+		return
+	}
+	if input = strings.TrimSpace(input); input == "" {
+		return defaultValue, nil
+	}
+
+	return input, nil
+}
+
+// GetString - Prompts for and reads a string from the console, an empty input gives the default value
+func GetString(prompt, defaultValue string) (string, error) {
+	return readString(stdinReader, prompt, defaultValue)
+}
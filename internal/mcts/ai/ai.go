@@ -1,150 +1,319 @@
 package ai
 
 import (
+	"bufio"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"github.com/gostonefire/filehashmap"
+	"github.com/gostonefire/filehashmap/crt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+/*
+	The AI DB is a file hash map per node tree with one record per player/state pair of interest.
+	Key is the same 17 bytes as a node key in the node tree, i.e. state high 8 bytes, state low 8 bytes and
+	a player byte (1 for the player that makes the first move). Value is:
+
+	Visits       8 bytes, visits of the state when it was recorded
+	Value        8 bytes, float64 bits of the value (points/visits) for the player
+*/
+
+// keyLength - Length of an AI DB key
+const keyLength int = 17
+
+// valueLength - Length of an AI DB value
+const valueLength int = 16
+
 // AI - Represents a structure for managing with statistics and models in the AI domain
 type AI struct {
 	AIFileName         string
+	AIMap              *filehashmap.FileHashMap
 	highValueThreshold float32
 	lowValueThreshold  float32
 	visitsThreshold    uint64
-	stateLength        int
-	ofInterest         map[string]bool
+	flushSize          int
+	buffer             map[string]record
+	mu                 sync.Mutex // Serializes access since the file hash map seeks and reads in shared files
 }
 
-// NewAI - Creates a new AI instance
+// record - Statistics for a player/state pair waiting in buffer to be flushed to the AI DB
+type record struct {
+	visits uint64
+	value  float64
+	remove bool
+}
+
+// NewAI - Creates a new AI instance using a new AI DB or an existing
 func NewAI(
 	dbName string,
 	highValueThreshold,
 	lowValueThreshold float32,
 	visitsThreshold uint64,
-	stateLength int,
+	uniqueStates int64,
+	flushSize int,
 	newDB bool,
 ) (
 	ai *AI,
 	err error,
 ) {
-	dFilePrefix := fmt.Sprintf("%s-aiDB-", dbName)
+	aiFileName := fmt.Sprintf("%s-aiDB", dbName)
+	mFile := fmt.Sprintf("%s-map.bin", aiFileName)
+	oFile := fmt.Sprintf("%s-ovfl.bin", aiFileName)
 
-	// Get existing db files and record new file index
-	var dirEntries []os.DirEntry
-	dirEntries, err = os.ReadDir(".")
+	legacyFiles, err := LegacyFiles(dbName)
 	if err != nil {
-		fmt.Printf("Error listing directory content, %s\n", err)
 		return
 	}
 
-	dbFiles := make([]string, 0)
-	lenPrefix := len(dFilePrefix)
-	maxIdx := -1
-	for _, e := range dirEntries {
-		if strings.HasPrefix(e.Name(), dFilePrefix) {
-			dbFiles = append(dbFiles, e.Name())
-			idx, _ := strconv.Atoi(e.Name()[lenPrefix : lenPrefix+1])
-			if idx > maxIdx {
-				maxIdx = idx
-			}
-		}
-	}
-	newIdx := maxIdx + 1
-
-	// Clear old db file(s) if indicated
+	// Clear old db file(s) if indicated, including any legacy text files
 	if newDB {
-		if err = removeExistingFiles(dbFiles); err != nil {
+		if err = removeExistingFiles(append(legacyFiles, mFile, oFile)); err != nil {
 			fmt.Println("Error while trying to remove existing AI DB files")
 			return
 		}
-		newIdx = 0
+	} else if len(legacyFiles) > 0 {
+		fmt.Printf("Found %d legacy AI DB text files, merge them using the aimerge command\n", len(legacyFiles))
 	}
 
-	// New file to fill
-	dFile := fmt.Sprintf("%s%d.txt", dFilePrefix, newIdx)
-
-	// Test to open or create the new AI file and then close immediately
-	df, err := os.OpenFile(dFile, os.O_RDWR|os.O_CREATE, 0644)
-	if err != nil {
-		fmt.Printf("Error while open or create %s, %s\n", dFile, err)
-		return
+	_, err1 := os.Stat(mFile)
+	_, err2 := os.Stat(oFile)
+	if err1 != nil || err2 != nil {
+		newDB = true
 	}
-	err = df.Close()
-	if err != nil {
-		fmt.Printf("Error while closing %s, %s\n", dFile, err)
-		return
+
+	var fhm *filehashmap.FileHashMap
+	if newDB {
+		fhm, _, err = filehashmap.NewFileHashMap(aiFileName, crt.SeparateChaining, int(uniqueStates), 2, keyLength, valueLength, nil)
+		if err != nil {
+			fmt.Printf("Error while creating AI DB FileHashMap, %s\n", err)
+			return
+		}
+	} else {
+		fhm, _, err = filehashmap.NewFromExistingFiles(aiFileName, nil)
+		if err != nil {
+			fmt.Printf("Error while opening AI DB FileHashMap, %s\n", err)
+			return
+		}
 	}
 
 	ai = &AI{
-		AIFileName:         dFile,
+		AIFileName:         aiFileName,
+		AIMap:              fhm,
 		highValueThreshold: highValueThreshold,
 		lowValueThreshold:  lowValueThreshold,
 		visitsThreshold:    visitsThreshold,
-		stateLength:        stateLength,
-		ofInterest:         make(map[string]bool),
+		flushSize:          flushSize,
+		buffer:             make(map[string]record),
 	}
 
 	return
 }
 
-// RecordStateStatistics - Records statistics for a player/state pair in the AI domain
-func (A *AI) RecordStateStatistics(player int, state string, visits uint64, points float64) {
+// RecordStateStatistics - Records statistics for a player/state pair in the AI domain. Pairs with a value in
+// between the low and high thresholds are not of interest and are removed if previously recorded. Buffered
+// statistics are written to the AI DB once the buffer is full.
+func (A *AI) RecordStateStatistics(player int, state string, visits uint64, points float64) (err error) {
+	A.mu.Lock()
+	defer A.mu.Unlock()
 
 	if visits < A.visitsThreshold {
 		return
 	}
 
-	state = fmt.Sprintf("%s%d", state, player)
-
-	value := float32(points / float64(visits))
+	key := string(db.StateKey(state, player == 1))
+	value := points / float64(visits)
 
-	if value >= A.highValueThreshold {
-		A.ofInterest[state] = true
-	} else if value <= A.lowValueThreshold {
-		A.ofInterest[state] = false
+	if float32(value) >= A.highValueThreshold || float32(value) <= A.lowValueThreshold {
+		A.buffer[key] = record{visits: visits, value: value}
 	} else {
-		delete(A.ofInterest, state)
+		A.buffer[key] = record{remove: true}
+	}
+
+	if len(A.buffer) >= A.flushSize {
+		err = A.flush()
+	}
+
+	return
+}
+
+// StateValue - Returns the value of a player/state pair if it is recorded in the AI domain, i.e. it is either
+// a good state (high value) or a bad state (low value) for the player
+func (A *AI) StateValue(player int, state string) (value float64, found bool) {
+	A.mu.Lock()
+	defer A.mu.Unlock()
+
+	key := db.StateKey(state, player == 1)
+
+	if r, ok := A.buffer[string(key)]; ok {
+		return r.value, !r.remove
+	}
+
+	if A.AIMap == nil {
+		return
+	}
+
+	buf, err := A.AIMap.Get(key)
+	if err != nil {
+		return
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf[8:])), true
+}
+
+// Flush - Writes buffered statistics to the AI DB and empties the buffer
+func (A *AI) Flush() (err error) {
+	A.mu.Lock()
+	defer A.mu.Unlock()
+
+	return A.flush()
+}
+
+// flush - Writes buffered statistics to the AI DB and empties the buffer, the caller holds the lock
+func (A *AI) flush() (err error) {
+	if A.AIMap == nil {
+		return
 	}
+
+	buf := make([]byte, valueLength)
+	for k, r := range A.buffer {
+		if r.remove {
+			_, err = A.AIMap.Pop([]byte(k))
+			if errors.Is(err, crt.NoRecordFound{}) {
+				err = nil
+			}
+		} else {
+			binary.LittleEndian.PutUint64(buf, r.visits)
+			binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(r.value))
+			err = A.AIMap.Set([]byte(k), buf)
+		}
+		if err != nil {
+			fmt.Printf("Error while writing state values to AI DB, %s\n", err)
+			return
+		}
+	}
+	A.buffer = make(map[string]record)
+
+	return
+}
+
+// bufferRecord - Buffers the record of a key, buffered records are written to the AI DB once the buffer is full
+func (A *AI) bufferRecord(key string, r record) (err error) {
+	A.mu.Lock()
+	defer A.mu.Unlock()
+
+	A.buffer[key] = r
+	if len(A.buffer) >= A.flushSize {
+		err = A.flush()
+	}
+
+	return
 }
 
 // WriteAndCloseBuffers - Supposed to be run before closing down application and ensures that whatever is still left
-// in buffers gets written to either AI DB or overflow DB
+// in buffers gets written to the AI DB before closing its files
 func (A *AI) WriteAndCloseBuffers() (err error) {
-	// Open AI file
-	df, err := os.OpenFile(A.AIFileName, os.O_RDWR|os.O_CREATE, 0644)
+	A.mu.Lock()
+	defer A.mu.Unlock()
+
+	if A.AIMap == nil {
+		return
+	}
+
+	err = A.flush()
+	A.AIMap.CloseFiles()
+	A.AIMap = nil
+
+	return
+}
+
+// MergeLegacyFile - Merges a legacy AI DB text file with lines of "player,state,label" into the AI DB. Legacy files
+// carry no statistics so merged records get the visits threshold as visits and a value from the label.
+// Legacy labels were learned with points credited to the player in turn rather than the player that made the move,
+// i.e. a label of 1 marks a state bad for the player given by the key. The label is therefore inverted, 1 giving
+// the value 0 and 0 the value 1. States ending a game of tic-tac-toe or vertical four in a row were recorded with the
+// winner as player and end up under keys that are never looked up.
+func (A *AI) MergeLegacyFile(fileName string) (nRecords int, err error) {
+	f, err := os.Open(fileName)
 	if err != nil {
-		fmt.Printf("Error while open or create %s, %s\n", A.AIFileName, err)
+		fmt.Printf("Error while opening %s, %s\n", fileName, err)
 		return
 	}
-	defer func(df *os.File) { _ = df.Close() }(df)
+	defer func(f *os.File) { _ = f.Close() }(f)
 
-	var kLen int
-	var player, state string
-	for k, v := range A.ofInterest {
-		kLen = len(k)
-		player = k[kLen-1:]
-		state = k[:kLen-1]
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+		if len(fields) != 3 {
+			continue
+		}
 
-		diff := A.stateLength - len(state)
-		if diff > 0 {
-			state = fmt.Sprintf("%0*d%s", diff, 0, state)
+		var player int
+		player, err = strconv.Atoi(fields[0])
+		if err != nil {
+			fmt.Printf("Error, malformed player in %s: %s\n", fileName, fields[0])
+			return
 		}
 
-		if v {
-			_, err = fmt.Fprintf(df, "%s,%s,1\n", player, state)
-		} else {
-			_, err = fmt.Fprintf(df, "%s,%s,0\n", player, state)
+		value := 1.0
+		if fields[2] == "1" {
+			value = 0
+		}
+
+		if err = A.bufferRecord(string(db.StateKey(strings.TrimLeft(fields[1], "0"), player == 1)), record{visits: A.visitsThreshold, value: value}); err != nil {
+			return
 		}
+		nRecords++
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Printf("Error while reading %s, %s\n", fileName, err)
+		return
+	}
+
+	err = A.Flush()
+
+	return
+}
+
+// LegacyFiles - Returns legacy AI DB text files (<dbName>-aiDB-<index>.txt) present in the folder of the AI DB,
+// sorted by index so that later runs come last
+func LegacyFiles(dbName string) (files []string, err error) {
+	dir := filepath.Dir(dbName)
+	prefix := fmt.Sprintf("%s-aiDB-", filepath.Base(dbName))
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Printf("Error listing directory content, %s\n", err)
+		return
+	}
+
+	indices := make(map[string]int)
+	for _, e := range dirEntries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".txt") {
+			continue
+		}
+		idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".txt"))
 		if err != nil {
-			fmt.Printf("Error while writing state values to file, %s\n", err)
+			continue
 		}
+		indices[filepath.Join(dir, name)] = idx
+		files = append(files, filepath.Join(dir, name))
 	}
+
+	sort.Slice(files, func(i, j int) bool { return indices[files[i]] < indices[files[j]] })
+
 	return
 }
 
-// removeExistingFiles - Removes any existing node related files if present
+// removeExistingFiles - Removes any existing AI DB related files if present
 func removeExistingFiles(files []string) error {
 	for _, file := range files {
 		if _, err := os.Stat(file); err == nil {
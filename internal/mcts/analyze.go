@@ -0,0 +1,145 @@
+package mcts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gostonefire/filehashmap/crt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"sort"
+)
+
+// Analysis - Statistics for a position as learned by the tree, values are for the player in turn
+type Analysis struct {
+	State     string
+	Player    string
+	Visits    uint64
+	NHidden   int
+	Actions   []ActionAnalysis
+	Variation []Action
+}
+
+// ActionAnalysis - Statistics for one action of an analysed position. Value is the mean value for the player in
+// turn when playing the action. Solved tells that the value of the action is proven, either since it ends the game
+// (Terminal) or from the subtree under it, and SolvedValue is then the proven value.
+type ActionAnalysis struct {
+	Action      Action
+	Visits      uint64
+	Value       float64
+	UCT         float64
+	Solved      bool
+	SolvedValue float64
+	Terminal    bool
+}
+
+// Analyze - Returns statistics for the position given by state and player in turn. Actions are sorted by visits
+// and the principal variation follows the most visited action from each node. Visits of the position are taken
+// as the sum of visits of its actions, and actions are solved walking at most conf.SolveNodeLimit nodes.
+func (T *Tree) Analyze(state, player string) (analysis Analysis, err error) {
+	node, err := T.NodeDB.GetNode(db.StateKey(state, player == T.PlayerA))
+	if err != nil {
+		fmt.Printf("Error, position not found in the tree: %s\n", err)
+		return
+	}
+	if node.Actions == nil {
+		fmt.Println("Error, position is not expanded in the tree")
+		return analysis, fmt.Errorf("error, position is not expanded in the tree")
+	}
+
+	analysis = Analysis{State: node.State, Player: node.Player, NHidden: node.NHiddenActions}
+	for _, a := range node.Actions {
+		analysis.Visits += a.Visits
+	}
+
+	solver := T.NewSolver(conf.SolveNodeLimit)
+	for _, a := range node.Actions {
+		actionAnalysis := ActionAnalysis{
+			Action: Action{X: a.X, Y: a.Y, Pass: a.Pass},
+			Visits: a.Visits,
+		}
+		if a.Visits > 0 {
+			actionAnalysis.Value = float64(a.Points) / 2 / float64(a.Visits)
+			actionAnalysis.UCT, _ = uct(analysis.Visits, a.Visits, a.Points, T.UCTConstant)
+		}
+
+		var child db.MCNode
+		if child, err = T.NodeDB.GetNode(a.ActionNodeKey); err != nil {
+			return
+		}
+		actionAnalysis.Terminal = child.IsEnd
+		if actionAnalysis.SolvedValue, actionAnalysis.Solved, err = solver.SolveAction(a, node.Player); err != nil {
+			return
+		}
+		analysis.Actions = append(analysis.Actions, actionAnalysis)
+	}
+	sort.SliceStable(analysis.Actions, func(i, j int) bool { return analysis.Actions[i].Visits > analysis.Actions[j].Visits })
+
+	analysis.Variation, err = T.principalVariation(node)
+
+	return
+}
+
+// principalVariation - Follows the most visited action from the node until reaching a node without visited actions
+func (T *Tree) principalVariation(node db.MCNode) (variation []Action, err error) {
+	visited := make(map[string]bool)
+	for node.Actions != nil && !visited[node.State+node.Player] {
+		visited[node.State+node.Player] = true
+
+		var best *db.Action
+		for i, a := range node.Actions {
+			if a.Visits > 0 && (best == nil || a.Visits > best.Visits) {
+				best = &node.Actions[i]
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		variation = append(variation, Action{X: best.X, Y: best.Y, Pass: best.Pass})
+		if node, err = T.NodeDB.GetNode(best.ActionNodeKey); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// MoveEvaluation - Tree statistics for a move played in a position. Best is the most visited action, values are for
+// the player making the move.
+type MoveEvaluation struct {
+	InTree      bool
+	PlayedFound bool
+	Played      ActionAnalysis
+	Best        ActionAnalysis
+}
+
+// EvaluateMove - Returns tree statistics for the action played in the position given by state and player in turn.
+// InTree is false if the position isn't expanded in the tree.
+func (T *Tree) EvaluateMove(state, player string, action Action) (evaluation MoveEvaluation, err error) {
+	node, err := T.NodeDB.GetNode(db.StateKey(state, player == T.PlayerA))
+	if errors.Is(err, crt.NoRecordFound{}) {
+		return evaluation, nil
+	} else if err != nil {
+		return
+	}
+	if node.Actions == nil {
+		return
+	}
+
+	evaluation.InTree = true
+	for _, a := range node.Actions {
+		actionAnalysis := ActionAnalysis{Action: Action{X: a.X, Y: a.Y, Pass: a.Pass}, Visits: a.Visits}
+		if a.Visits > 0 {
+			actionAnalysis.Value = float64(a.Points) / 2 / float64(a.Visits)
+		}
+
+		if actionAnalysis.Action == action {
+			evaluation.Played, evaluation.PlayedFound = actionAnalysis, true
+		}
+		if a.Visits > evaluation.Best.Visits {
+			evaluation.Best = actionAnalysis
+		}
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+)
+
+// Annotation marks of played moves
+const (
+	MarkInaccuracy string = "?!"
+	MarkBlunder    string = "??"
+)
+
+// Annotation - Tree judgement of a played move. Drop is the value of the most visited action minus the value of
+// the played action, both for the player making the move, and Judged tells whether the played action had enough
+// visits to be compared at all.
+type Annotation struct {
+	Ply        int
+	Player     string
+	Action     Action
+	Evaluation MoveEvaluation
+	Judged     bool
+	Drop       float64
+	Mark       string
+}
+
+// Comment - Returns the annotation as a game record comment
+func (A *Annotation) Comment() string {
+	if !A.Evaluation.InTree {
+		return "position not in tree"
+	}
+
+	var comment string
+	switch A.Mark {
+	case MarkBlunder:
+		comment = "?? blunder, "
+	case MarkInaccuracy:
+		comment = "?! inaccuracy, "
+	}
+
+	if A.Evaluation.PlayedFound {
+		comment += fmt.Sprintf("played visits=%d value=%.3f", A.Evaluation.Played.Visits, A.Evaluation.Played.Value)
+	} else {
+		comment += "played move not in tree"
+	}
+	if A.Evaluation.Best.Action != A.Action {
+		comment += fmt.Sprintf(", best %s visits=%d value=%.3f",
+			FormatMove(A.Evaluation.Best.Action), A.Evaluation.Best.Visits, A.Evaluation.Best.Value)
+	}
+
+	return comment
+}
+
+// Annotate - Replays a game record through the game and judges every move against the most visited action of the
+// tree. A move is marked as an inaccuracy or a blunder by its value drop (see InaccuracyValueDrop and
+// BlunderValueDrop in conf). It returns a copy of the record with the annotations as comments, kept after any
+// comment already in the record, together with the annotations.
+func (T *Tree) Annotate(record *GameRecord) (annotated *GameRecord, annotations []Annotation, err error) {
+	state, player, err := T.ParsePosition(record.Position)
+	if err != nil {
+		return
+	}
+	T.Game.SetState(state, player)
+
+	annotated = T.NewGameRecord(record.Position, player, record.Engine, record.Date)
+	if record.Game != "" {
+		annotated.Game = record.Game
+	}
+	annotated.Result = record.Result
+
+	for i, m := range record.Moves {
+		state, player = T.Game.GetState()
+
+		annotation := Annotation{Ply: i + 1, Player: player, Action: m.Action}
+		if annotation.Evaluation, err = T.EvaluateMove(state, player, m.Action); err != nil {
+			return
+		}
+		annotation.judge()
+		annotations = append(annotations, annotation)
+
+		comment := annotation.Comment()
+		if m.Comment != "" {
+			comment = m.Comment + "; " + comment
+		}
+		annotated.AddMove(m.Action, comment)
+
+		isDone, winner, moveErr := T.Game.Move(m.Action.X, m.Action.Y, m.Action.Pass)
+		if moveErr != nil {
+			fmt.Printf("Illegal move %s at ply %d\n", FormatMove(m.Action), i+1)
+			return nil, nil, fmt.Errorf("illegal move %s at ply %d: %s", FormatMove(m.Action), i+1, moveErr)
+		}
+		if isDone {
+			annotated.SetWinner(winner)
+			break
+		}
+	}
+
+	return
+}
+
+// judge - Sets drop and mark of the annotation given its evaluation
+func (A *Annotation) judge() {
+	if !A.Evaluation.PlayedFound || A.Evaluation.Played.Visits < conf.AnnotateMinVisits {
+		return
+	}
+
+	A.Judged = true
+	A.Drop = A.Evaluation.Best.Value - A.Evaluation.Played.Value
+	if A.Drop >= conf.BlunderValueDrop {
+		A.Mark = MarkBlunder
+	} else if A.Drop >= conf.InaccuracyValueDrop {
+		A.Mark = MarkInaccuracy
+	}
+}
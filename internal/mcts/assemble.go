@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"github.com/gostonefire/go-mcts-v3/internal/conf"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts/ai"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/book"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/runs"
+	"github.com/gostonefire/go-mcts-v3/internal/nn"
 	"github.com/gostonefire/go-mcts-v3/internal/othello"
 	"github.com/gostonefire/go-mcts-v3/internal/tictactoe"
 	"github.com/gostonefire/go-mcts-v3/internal/verticalfourinarow"
+	"os"
 )
 
 // AssembleForLearning - Assembles all parts necessary for learning mode
@@ -17,45 +21,38 @@ func AssembleForLearning() (
 	err error,
 ) {
 
-	var game BoardGame
-	var playerA, playerB string
-
 	// Get options from console
-	gameId, size, maxRounds, uniqueStates, forceNew, name, err := conf.GetLearnOptions()
+	options, err := conf.GetLearnOptions()
 	if err != nil {
 		return
 	}
+	name := options.Name
 
 	deferFunc = func() {}
 
+	// Leaf evaluators are only used at the playout cutoff
+	if options.LeafEvaluator == NetworkLeafEvaluator && options.PlayoutCutoff <= 0 {
+		fmt.Println("Error, the network leaf evaluator requires a playout cutoff")
+		err = fmt.Errorf("error, the network leaf evaluator requires a playout cutoff")
+		return
+	}
+
+	// Create or update the run to learn in
+	if err = runs.Prepare(options.Run, options.GameId, options.Size); err != nil {
+		return
+	}
+
 	// Create the game instance
-	if gameId == 0 {
-		playerA = "X"
-		playerB = "Y"
-		game = tictactoe.NewTicTacToe(size, playerA, playerB)
-	} else if gameId == 1 {
-		playerA = "B"
-		playerB = "W"
-		game, err = othello.NewOthello(size, playerA, playerB)
-		if err != nil {
-			return
-		}
-	} else if gameId == 2 {
-		playerA = "B"
-		playerB = "W"
-		game = verticalfourinarow.NewVerticalFIR(playerA, playerB)
-		if err != nil {
-			return
-		}
-	} else {
-		fmt.Println("No game corresponding to given game number")
-		err = fmt.Errorf("error, no game corresponding to given game number")
+	game, _, err := NewBoardGame(options.GameId, options.Size)
+	if err != nil {
 		return
 	}
+	players := game.GetPlayers()
+	playerA, playerB := players[0], players[1]
 	initialState, _ := game.GetState()
 
 	// Create the node tree db instance
-	nodeDB, err := db.NewNodeTree(name, playerA, playerB, initialState, uniqueStates, forceNew)
+	nodeDB, err := db.NewNodeTree(name, playerA, playerB, initialState, options.UniqueStates, options.ForceNew)
 	if err != nil {
 		fmt.Println("Error while creating file based node database")
 		err = fmt.Errorf("error while creating file based node database")
@@ -67,15 +64,84 @@ func AssembleForLearning() (
 	}
 
 	// Create AI management assets
-	aiMgmt, err := ai.NewAI(name, conf.AIHighValueThreshold, conf.AILowValueThreshold, conf.AIVisitsThreshold, int(size*size), forceNew)
+	aiMgmt, err := ai.NewAI(name, conf.AIHighValueThreshold, conf.AILowValueThreshold, conf.AIVisitsThreshold, conf.AIUniqueStates, conf.AIFlushSize, options.ForceNew)
 	if err != nil {
 		fmt.Println("Error while creating AI management assets")
 		err = fmt.Errorf("error while creating AI management assets")
 		return
 	}
+	closeNodeDB := deferFunc
+	deferFunc = func() {
+		_ = aiMgmt.WriteAndCloseBuffers()
+		closeNodeDB()
+	}
+
+	// Load the network if it is to be used as prior model or leaf evaluator
+	var network *nn.Network
+	if (options.Selection == PUCTSelection && options.PriorModel == NetworkPrior) || options.LeafEvaluator == NetworkLeafEvaluator {
+		network, err = nn.Load(fmt.Sprintf("%s-nn.bin", name))
+		if err != nil {
+			fmt.Println("Error while loading network, train one first")
+			return
+		}
+		if err = CheckGameNetwork(game, network); err != nil {
+			return
+		}
+	}
+
+	// Create the policy model giving priors when selecting using PUCT
+	var policyModel PolicyModel
+	if options.Selection == PUCTSelection {
+		policyModel, err = NewPolicyModel(options.PriorModel, aiMgmt, network, conf.PriorTemperature)
+		if err != nil {
+			return
+		}
+	}
 
 	// Create the mcts tree instance
-	tree = NewTree(game, nodeDB, aiMgmt, maxRounds, fmt.Sprintf("%s.state", name), forceNew)
+	tree = NewTree(game, nodeDB, aiMgmt, options.PlayoutCutoff, policyModel, options.MaxRounds, options.RoundsMode, options.Seed, fmt.Sprintf("%s.state", name), options.ForceNew)
+	if tree == nil {
+		err = fmt.Errorf("error while creating the mcts tree")
+		return
+	}
+	tree.Name = name
+	tree.Run = options.Run
+	tree.Stop = StopConditionsFromOptions(options.TimeBudget, options.NodeBudget, options.DiskBudget, options.StableChecks, options.StopIfSolved)
+
+	// Create the playout policy used in simulations, drawing from the random generator of the tree
+	playoutPolicy, err := NewPlayoutPolicy(options.PlayoutPolicy, conf.MASTTemperature, tree.Rand)
+	if err != nil {
+		return
+	}
+	tree.SetPlayoutPolicy(playoutPolicy)
+
+	if options.LeafEvaluator == NetworkLeafEvaluator {
+		tree.LeafEvaluator = NewNetworkEvaluator(network, game)
+	}
+
+	// Evaluate strength of the tree against baseline players at intervals
+	if options.EvaluateEvery > 0 && options.EvalGames > 0 {
+		newGame := func() (BoardGame, error) {
+			evalGame, _, gameErr := NewBoardGame(options.GameId, options.Size)
+			return evalGame, gameErr
+		}
+		tree.StrengthEvaluator = NewStrengthEvaluator(int64(options.EvaluateEvery), options.EvalGames, fmt.Sprintf("%s-eval.csv", name), newGame, tree.Rounds)
+	}
+
+	// Seed statistics of a fresh tree from the opening book
+	if options.SeedBook && tree.Rounds == 0 {
+		var openingBook *book.Book
+		openingBook, err = book.Load(fmt.Sprintf("%s.book", name))
+		if err != nil {
+			return
+		}
+		var nSeeded int
+		if nSeeded, err = tree.SeedFromBook(openingBook); err != nil {
+			fmt.Println("Error while seeding tree from opening book")
+			return
+		}
+		fmt.Printf("Seeded %d actions from opening book\n", nSeeded)
+	}
 
 	return
 }
@@ -88,9 +154,6 @@ func AssembleForPlay() (
 	err error,
 ) {
 
-	var game BoardGame
-	var playerA, playerB string
-
 	// Get options from console
 	gameId, size, name, err := conf.GetPlayOptions()
 	if err != nil {
@@ -99,24 +162,32 @@ func AssembleForPlay() (
 
 	deferFunc = func() {}
 
-	if gameId == 0 {
-		playerA = "X"
-		playerB = "Y"
-		game = tictactoe.NewTicTacToe(size, playerA, playerB)
-	} else if gameId == 1 {
-		playerA = "B"
-		playerB = "W"
-		game, err = othello.NewOthello(size, playerA, playerB)
-		if err != nil {
-			return
-		}
-		passAllowed = true
-	} else {
-		fmt.Println("No game corresponding to given game number")
-		err = fmt.Errorf("error, no game corresponding to given game number")
+	game, passAllowed, err := NewBoardGame(gameId, size)
+	if err != nil {
+		return
+	}
+
+	tree, deferFunc, err = OpenPlayTree(game, name)
+
+	return
+}
+
+// OpenPlayTree - Opens the named node tree with its AI DB and opening book (if any) read only for play with the
+// given game instance
+func OpenPlayTree(game BoardGame, name string) (
+	tree *Tree,
+	deferFunc func(),
+	err error,
+) {
+
+	deferFunc = func() {}
+
+	if err = runs.CheckTree(name); err != nil {
 		return
 	}
 
+	players := game.GetPlayers()
+	playerA, playerB := players[0], players[1]
 	initialState, _ := game.GetState()
 	nodeDB, err := db.NewPlayNodeTree(name, playerA, playerB, initialState)
 	if err != nil {
@@ -130,14 +201,164 @@ func AssembleForPlay() (
 	}
 
 	// Create AI management assets
-	aiMgmt, err := ai.NewAI(name, conf.AIHighValueThreshold, conf.AILowValueThreshold, conf.AIVisitsThreshold, int(size*size), false)
+	aiMgmt, err := ai.NewAI(name, conf.AIHighValueThreshold, conf.AILowValueThreshold, conf.AIVisitsThreshold, conf.AIUniqueStates, conf.AIFlushSize, false)
 	if err != nil {
 		fmt.Println("Error while creating AI management assets")
 		err = fmt.Errorf("error while creating AI management assets")
 		return
 	}
+	closeNodeDB := deferFunc
+	deferFunc = func() {
+		_ = aiMgmt.WriteAndCloseBuffers()
+		closeNodeDB()
+	}
 
 	tree = NewPlayTree(game, nodeDB, aiMgmt, fmt.Sprintf("%s.state", name))
+	if tree == nil {
+		err = fmt.Errorf("error while creating the mcts tree")
+		return
+	}
+	tree.Name = name
+	tree.Run = runs.RunOf(name)
+
+	// Use the opening book if there is one
+	bookFile := fmt.Sprintf("%s.book", name)
+	if _, statErr := os.Stat(bookFile); statErr == nil {
+		if tree.Book, err = book.Load(bookFile); err != nil {
+			return
+		}
+		fmt.Printf("Using opening book with %d positions\n", tree.Book.Len())
+	}
+
+	return
+}
+
+// AssembleForReading - Assembles a node tree opened read only for commands inspecting it
+func AssembleForReading() (
+	tree *Tree,
+	deferFunc func(),
+	err error,
+) {
+
+	// Get options from console
+	gameId, size, name, err := conf.GetPlayOptions()
+	if err != nil {
+		return
+	}
+
+	deferFunc = func() {}
+
+	game, _, err := NewBoardGame(gameId, size)
+	if err != nil {
+		return
+	}
+
+	return OpenReadOnlyTree(game, name)
+}
+
+// OpenReadOnlyTree - Opens the named node tree read only with the given game instance. No file is created or
+// changed, and there is neither an AI DB nor an opening book, so the tree can be inspected but not played.
+func OpenReadOnlyTree(game BoardGame, name string) (
+	tree *Tree,
+	deferFunc func(),
+	err error,
+) {
+
+	deferFunc = func() {}
+
+	if err = runs.CheckTree(name); err != nil {
+		return
+	}
+
+	players := game.GetPlayers()
+	nodeDB, err := db.NewReadOnlyNodeTree(name, players[0], players[1])
+	if err != nil {
+		fmt.Println("Error while open file based node database")
+		err = fmt.Errorf("error while open file based node database")
+		return
+	}
+	deferFunc = nodeDB.Close
+
+	tree = NewPlayTree(game, nodeDB, nil, fmt.Sprintf("%s.state", name))
+	if tree == nil {
+		err = fmt.Errorf("error while creating the mcts tree")
+		return
+	}
+	tree.Name = name
+	tree.Run = runs.RunOf(name)
+
+	return
+}
+
+// OpenCheckTree - Opens the named node tree for checking with the given game instance, for writing if it is to be
+// repaired and otherwise read only
+func OpenCheckTree(game BoardGame, name string, repair bool) (
+	tree *Tree,
+	deferFunc func(),
+	err error,
+) {
+
+	deferFunc = func() {}
+
+	// Checking must never create a new node tree
+	if err = runs.CheckTree(name); err != nil {
+		return
+	}
+	if err = db.RecoverResize(name); err != nil {
+		return
+	}
+	mFile := fmt.Sprintf("%s-map.bin", name)
+	if _, err = os.Stat(mFile); err != nil {
+		fmt.Printf("Error, no node tree named %s\n", name)
+		return
+	}
+
+	players := game.GetPlayers()
+	playerA, playerB := players[0], players[1]
+	initialState, _ := game.GetState()
+
+	var nodeDB *db.NodeTree
+	if repair {
+		nodeDB, err = db.NewNodeTree(name, playerA, playerB, initialState, 0, false)
+	} else {
+		nodeDB, err = db.NewPlayNodeTree(name, playerA, playerB, initialState)
+	}
+	if err != nil {
+		fmt.Println("Error while open file based node database")
+		err = fmt.Errorf("error while open file based node database")
+		return
+	}
+	deferFunc = func() {
+		_ = nodeDB.ActionsFile.Close()
+		nodeDB.NodeMap.CloseFiles()
+	}
+
+	tree = NewPlayTree(game, nodeDB, nil, fmt.Sprintf("%s.state", name))
+	if tree == nil {
+		err = fmt.Errorf("error while creating the mcts tree")
+		return
+	}
+	tree.Name = name
+	tree.Run = runs.RunOf(name)
+
+	return
+}
+
+// NewBoardGame - Returns a new game instance corresponding to the game number and size, and whether the game
+// allows passing
+func NewBoardGame(gameId int, size uint8) (game BoardGame, passAllowed bool, err error) {
+	switch gameId {
+	case 0:
+		game = tictactoe.NewTicTacToe(size, "X", "Y")
+	case 1:
+		game, err = othello.NewOthello(size, "B", "W")
+		passAllowed = true
+	case 2:
+		game = verticalfourinarow.NewVerticalFIR("B", "W")
+	default:
+		fmt.Println("No game corresponding to given game number")
+		err = fmt.Errorf("error, no game corresponding to given game number")
+	}
 
 	return
 }
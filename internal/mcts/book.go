@@ -0,0 +1,160 @@
+package mcts
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/book"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
+	"sort"
+)
+
+// BuildBook - Builds an opening book from the most visited lines of the node tree. At every node the at most
+// breadth (zero gives no limit) most visited actions with at least minVisits visits are added to the book and
+// followed until maxDepth plies have been played (zero gives no limit).
+func (T *Tree) BuildBook(minVisits uint64, maxDepth, breadth int) (*book.Book, error) {
+	action, err := T.NodeDB.GetTopAction()
+	if err != nil {
+		return nil, err
+	}
+
+	b := book.NewBook()
+	visited := make(map[string]bool)
+	err = T.bookNode(b, action.ActionNode, 1, minVisits, maxDepth, breadth, visited)
+
+	return b, err
+}
+
+// bookNode - Adds the most visited actions of a node to the book and continues with their nodes
+func (T *Tree) bookNode(b *book.Book, node db.MCNode, depth int, minVisits uint64, maxDepth, breadth int, visited map[string]bool) (err error) {
+	key := node.State + node.Player
+	if visited[key] || maxDepth > 0 && depth > maxDepth {
+		return
+	}
+	visited[key] = true
+
+	children := make([]db.Action, 0, len(node.Actions))
+	for _, a := range node.Actions {
+		if a.Visits >= minVisits && a.Visits > 0 {
+			children = append(children, a)
+		}
+	}
+	sort.SliceStable(children, func(i, j int) bool { return children[i].Visits > children[j].Visits })
+	if breadth > 0 && len(children) > breadth {
+		children = children[:breadth]
+	}
+	if len(children) == 0 {
+		return
+	}
+
+	// Points on an action are given to the player that made it, i.e. the player in turn at this node
+	moves := make([]book.Move, len(children))
+	for i, a := range children {
+		moves[i] = book.Move{
+			X:      a.X,
+			Y:      a.Y,
+			Pass:   a.Pass,
+			Visits: a.Visits,
+			Value:  float64(a.Points) / 2 / float64(a.Visits),
+		}
+	}
+	b.Add(node.State, node.Player, moves)
+
+	for _, a := range children {
+		var child db.MCNode
+		if child, err = T.NodeDB.GetNode(a.ActionNodeKey); err != nil {
+			return
+		}
+		if err = T.bookNode(b, child, depth+1, minVisits, maxDepth, breadth, visited); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// SeedFromBook - Seeds statistics of the tree from an opening book. Book positions are expanded as in learning and
+// actions matching book moves get the visits and points of the book. It is meant for a fresh tree before learning.
+// With progressive widening only exposed actions can be seeded. It returns number of seeded actions.
+func (T *Tree) SeedFromBook(b *book.Book) (nSeeded int, err error) {
+	action, err := T.NodeDB.GetTopAction()
+	if err != nil {
+		return
+	}
+
+	var visits uint64
+	for _, m := range b.Moves(action.ActionNode.State, action.ActionNode.Player) {
+		visits += m.Visits
+	}
+	if visits == 0 {
+		return
+	}
+
+	action.Visits = visits
+	if err = T.NodeDB.UpdateActionStatistics(action.ActionsAddress, action.ActionIndex, action.Visits, action.Points); err != nil {
+		return
+	}
+
+	return T.seedNode(b, []db.Action{action})
+}
+
+// seedNode - Expands the node attached to the last action if in the book and seeds its book actions
+func (T *Tree) seedNode(b *book.Book, actions []db.Action) (nSeeded int, err error) {
+	last := len(actions) - 1
+	moves := b.Moves(actions[last].ActionNode.State, actions[last].ActionNode.Player)
+	if len(moves) == 0 || actions[last].ActionNode.IsEnd {
+		return
+	}
+
+	if actions[last].ActionNode.Actions == nil {
+		if _, err = T.Expand(actions); err != nil {
+			return
+		}
+	}
+
+	var n int
+	for _, m := range moves {
+		for _, a := range actions[last].ActionNode.Actions {
+			if a.X != m.X || a.Y != m.Y || a.Pass != m.Pass {
+				continue
+			}
+
+			a.Visits = m.Visits
+			a.Points = uint64(math.Round(2 * m.Value * float64(m.Visits)))
+			if err = T.NodeDB.UpdateActionStatistics(a.ActionsAddress, a.ActionIndex, a.Visits, a.Points); err != nil {
+				return
+			}
+			nSeeded++
+
+			if a.ActionNode, err = T.NodeDB.GetNode(a.ActionNodeKey); err != nil {
+				return
+			}
+			if n, err = T.seedNode(b, append(actions[:last+1:last+1], a)); err != nil {
+				return
+			}
+			nSeeded += n
+		}
+	}
+
+	return
+}
+
+// bookAction - Returns the best book move (see Book.Best) for the current position of the given game if the
+// tree has a book and the move is legal
+func (T *Tree) bookAction(game BoardGame) (action Action, move book.Move, found bool) {
+	if T.Book == nil {
+		return
+	}
+
+	state, player := game.GetState()
+	move, inBook := T.Book.Best(state, player)
+	if !inBook {
+		return
+	}
+
+	for _, a := range gameActions(game) {
+		if a.X == move.X && a.Y == move.Y && a.Pass == move.Pass {
+			return a, move, true
+		}
+	}
+
+	return
+}
@@ -0,0 +1,193 @@
+package book
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+	Book file format, one position per line with space separated fields:
+
+	State          base 3 state string with leading zeros trimmed
+	Player         player in turn
+	Moves          one field per book move as move,visits,value where move is x:y or pass and value is the win
+	               rate for the player in turn when playing the move, e.g. "2:3,1520,0.6132"
+
+	Lines starting with # are comments.
+*/
+
+// Move - A book move with its statistics, value is the win rate for the player making the move
+type Move struct {
+	X      uint8
+	Y      uint8
+	Pass   bool
+	Visits uint64
+	Value  float64
+}
+
+// Book - An opening book keyed by state and player in turn
+type Book struct {
+	entries map[string][]Move
+}
+
+// NewBook - Returns a new empty book
+func NewBook() *Book {
+	return &Book{entries: make(map[string][]Move)}
+}
+
+// Load - Loads a book from file
+func Load(fileName string) (book *Book, err error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		fmt.Printf("Error while opening %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	book = NewBook()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			fmt.Printf("Error, malformed book line: %s\n", line)
+			return nil, fmt.Errorf("error, malformed book line: %s", line)
+		}
+
+		moves := make([]Move, 0, len(fields)-2)
+		for _, field := range fields[2:] {
+			var move Move
+			if move, err = parseMove(field); err != nil {
+				return nil, err
+			}
+			moves = append(moves, move)
+		}
+		book.Add(fields[0], fields[1], moves)
+	}
+	if err = scanner.Err(); err != nil {
+		fmt.Printf("Error while reading %s, %s\n", fileName, err)
+		return nil, err
+	}
+
+	return
+}
+
+// Save - Saves the book to file, positions are sorted to give a stable file
+func (B *Book) Save(fileName string) (err error) {
+	f, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	keys := make([]string, 0, len(B.entries))
+	for k := range B.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w := bufio.NewWriter(f)
+	_, _ = fmt.Fprintln(w, "# state player move,visits,value ...")
+	for _, k := range keys {
+		_, _ = w.WriteString(k)
+		for _, m := range B.entries[k] {
+			_, _ = fmt.Fprintf(w, " %s,%d,%.4f", formatMove(m), m.Visits, m.Value)
+		}
+		_, _ = w.WriteString("\n")
+	}
+
+	if err = w.Flush(); err != nil {
+		fmt.Printf("Error while writing book to file: %s\n", err)
+	}
+
+	return
+}
+
+// Add - Adds or replaces the moves for a state and player in turn, moves are kept sorted by visits
+func (B *Book) Add(state, player string, moves []Move) {
+	sorted := make([]Move, len(moves))
+	copy(sorted, moves)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Visits > sorted[j].Visits })
+
+	B.entries[key(state, player)] = sorted
+}
+
+// Moves - Returns the book moves for a state and player in turn sorted by visits, nil if not in book
+func (B *Book) Moves(state, player string) []Move {
+	return B.entries[key(state, player)]
+}
+
+// Best - Returns the most visited book move for a state and player in turn, ties broken by win rate. Visits are
+// preferred over win rate since a high win rate of a rarely visited move is mostly noise.
+func (B *Book) Best(state, player string) (best Move, found bool) {
+	for _, m := range B.entries[key(state, player)] {
+		if !found || m.Visits > best.Visits || m.Visits == best.Visits && m.Value > best.Value {
+			best, found = m, true
+		}
+	}
+
+	return
+}
+
+// Len - Returns number of positions in the book
+func (B *Book) Len() int {
+	return len(B.entries)
+}
+
+// key - Returns the book key for a state and player in turn
+func key(state, player string) string {
+	state = strings.TrimLeft(state, "0")
+	if state == "" {
+		state = "0"
+	}
+
+	return fmt.Sprintf("%s %s", state, player)
+}
+
+// formatMove - Formats the move part of a book move
+func formatMove(m Move) string {
+	if m.Pass {
+		return "pass"
+	}
+
+	return fmt.Sprintf("%d:%d", m.X, m.Y)
+}
+
+// parseMove - Parses a book move field
+func parseMove(field string) (move Move, err error) {
+	parts := strings.Split(field, ",")
+	if len(parts) != 3 {
+		fmt.Printf("Error, malformed book move: %s\n", field)
+		return move, fmt.Errorf("error, malformed book move: %s", field)
+	}
+
+	if parts[0] == "pass" {
+		move.Pass = true
+	} else {
+		var x, y int
+		if _, err = fmt.Sscanf(parts[0], "%d:%d", &x, &y); err != nil {
+			fmt.Printf("Error, malformed book move: %s\n", field)
+			return move, fmt.Errorf("error, malformed book move: %s", field)
+		}
+		move.X, move.Y = uint8(x), uint8(y)
+	}
+
+	if move.Visits, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		fmt.Printf("Error, malformed visits in book move: %s\n", field)
+		return
+	}
+	if move.Value, err = strconv.ParseFloat(parts[2], 64); err != nil {
+		fmt.Printf("Error, malformed value in book move: %s\n", field)
+	}
+
+	return
+}
@@ -0,0 +1,305 @@
+package mcts
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
+	"os"
+)
+
+// Kinds of problems found when checking a node tree
+const (
+	ProblemActionsRecord = "actions record"
+	ProblemMissingNode   = "missing node"
+	ProblemChildState    = "child state"
+	ProblemPoints        = "points"
+	ProblemVisits        = "visits"
+	ProblemEndNode       = "end node"
+	ProblemCounters      = "counters"
+)
+
+// CheckProblem - One problem found when checking a node tree, Position is the node having the problem
+type CheckProblem struct {
+	Kind     string
+	Position string
+	Detail   string
+	Repaired bool
+	repair   func() error
+}
+
+// CheckReport - Summary of a node tree check. Node counts are as found in the node tree after any repairs and
+// unreferenced nodes are nodes, other than the top node, that no exposed action leads to.
+type CheckReport struct {
+	NNodes           int64
+	NUnexpandedNodes int64
+	NEndNodes        int64
+	NUnreferenced    int64
+	NActions         int64
+	NHiddenActions   int64
+	Problems         map[string]int
+	NRepaired        int
+}
+
+// Check - Checks the integrity of the node tree and calls fn with every problem found. Each node is checked for a
+// well-formed actions record, that the nodes of its exposed actions exist and are the states given by playing the
+// actions, that points of its actions don't exceed twice the visits, that its visits roughly equal the visits of
+// its actions and that it has no actions if it is an end node. Counters in the state file are checked against the
+// node tree. If repair is true the problems that can be repaired are, which requires the node tree to be opened
+// for writing.
+func (T *Tree) Check(repair bool, fn func(problem CheckProblem)) (report CheckReport, err error) {
+	nodeTree, ok := T.NodeDB.(*db.NodeTree)
+	if !ok {
+		fmt.Println("Error, checking requires a file based node tree")
+		return report, fmt.Errorf("error, checking requires a file based node tree")
+	}
+	report.Problems = make(map[string]int)
+
+	// The top action is the only action not belonging to a node and holds the total visits of the top node
+	top, err := nodeTree.ReadTopActionsRecord()
+	if err != nil {
+		fmt.Printf("Error, malformed top action: %s\n", err)
+		return
+	}
+	incoming := map[string]*incomingVisits{string(top[0].ActionNodeKey): {visits: top[0].Visits, nParents: 1}}
+	outgoing := make(map[string]uint64)
+	missing := make(map[string]bool)
+
+	var problems []CheckProblem
+	addProblem := func(node db.MCNode, kind, detail string, repairFunc func() error) {
+		problems = append(problems, CheckProblem{
+			Kind:     kind,
+			Position: T.Game.FormatPosition(node.State, node.Player),
+			Detail:   detail,
+			repair:   repairFunc,
+		})
+	}
+
+	// Repairs are collected and made after the scan since the hash map files can't be written while scanned
+	err = nodeTree.ScanNodes(func(nodeKey []byte, node db.MCNode) (err error) {
+		report.NNodes++
+		if node.IsEnd {
+			report.NEndNodes++
+		}
+		if node.ActionsAddress == math.MaxUint64 {
+			if !node.IsEnd {
+				report.NUnexpandedNodes++
+			}
+			return
+		}
+
+		actions, recordErr := nodeTree.ReadActionsRecord(node.ActionsAddress)
+		if recordErr != nil {
+			addProblem(node, ProblemActionsRecord, recordErr.Error(), func() error {
+				return nodeTree.SetNode(nodeKey, node.IsEnd, math.MaxUint64)
+			})
+			return
+		}
+
+		if node.IsEnd {
+			addProblem(node, ProblemEndNode, fmt.Sprintf("end node has %d actions", len(actions)), func() error {
+				if isDone, _ := T.Game.SetState(node.State, node.Player); isDone {
+					return nodeTree.SetNode(nodeKey, true, math.MaxUint64)
+				}
+				return nodeTree.SetNode(nodeKey, false, node.ActionsAddress)
+			})
+		}
+
+		var sum uint64
+		for _, a := range actions {
+			report.NActions++
+			if a.Hidden {
+				report.NHiddenActions++
+			}
+			sum += a.Visits
+			move := FormatMove(Action{X: a.X, Y: a.Y, Pass: a.Pass})
+
+			if a.Points > 2*a.Visits {
+				a := a
+				detail := fmt.Sprintf("action %s has %d points for %d visits", move, a.Points, a.Visits)
+				addProblem(node, ProblemPoints, detail, func() error {
+					return nodeTree.UpdateActionStatistics(a.ActionsAddress, a.ActionIndex, a.Visits, 2*a.Visits)
+				})
+			}
+
+			// An action leading to the wrong node is accounted for as leading to the right one, as after repair
+			childKey := a.ActionNodeKey
+			if !node.IsEnd {
+				if detail, key := T.checkChildState(node, a); detail != "" {
+					var repairFunc func() error
+					if key != nil {
+						a := a
+						childKey = key
+						repairFunc = func() error {
+							if !a.Hidden {
+								if _, addErr := nodeTree.AddNode(key); addErr != nil {
+									return addErr
+								}
+							}
+							return nodeTree.SetActionNodeKey(a.ActionsAddress, a.ActionIndex, key)
+						}
+					}
+					addProblem(node, ProblemChildState, fmt.Sprintf("action %s %s", move, detail), repairFunc)
+				}
+			}
+
+			// Hidden actions have no node until exposed
+			if a.Hidden {
+				continue
+			}
+			in, ok := incoming[string(childKey)]
+			if !ok {
+				in = &incomingVisits{}
+				incoming[string(childKey)] = in
+			}
+			in.visits += a.Visits
+			in.nParents++
+
+			var found bool
+			if found, err = nodeTree.HasNode(childKey); err != nil {
+				return
+			}
+			if !found && !missing[string(childKey)] {
+				missing[string(childKey)] = true
+
+				// Actions leading to the wrong node get the right node added when repaired
+				if bytes.Equal(childKey, a.ActionNodeKey) {
+					addProblem(node, ProblemMissingNode, fmt.Sprintf("node of action %s doesn't exist", move), func() error {
+						_, addErr := nodeTree.AddNode(childKey)
+						return addErr
+					})
+				}
+			}
+		}
+		if !node.IsEnd {
+			outgoing[string(nodeKey)] = sum
+		}
+
+		return
+	})
+	if err != nil {
+		fmt.Printf("Error while scanning node tree: %s\n", err)
+		return
+	}
+
+	// Visits of a node is the sum of visits of the actions leading to it. Its actions are visited once less for
+	// every parent that picked it as the random child when expanding, except for parents expanded after the node.
+	for key, sum := range outgoing {
+		var in incomingVisits
+		if incoming[key] != nil {
+			in = *incoming[key]
+		}
+		tolerance := conf.CheckVisitsTolerance * float64(in.visits)
+		if float64(sum) > float64(in.visits)+tolerance || float64(in.visits) > float64(sum)+float64(in.nParents)+tolerance {
+			state, playerA := db.KeyState([]byte(key))
+			node := db.MCNode{State: state, Player: T.player(playerA)}
+			detail := fmt.Sprintf("node has %d visits from %d parents but its actions %d", in.visits, in.nParents, sum)
+			addProblem(node, ProblemVisits, detail, nil)
+		}
+	}
+
+	if repair {
+		for i := range problems {
+			if problems[i].repair == nil {
+				continue
+			}
+			if err = problems[i].repair(); err != nil {
+				return
+			}
+			problems[i].Repaired = true
+			report.NRepaired++
+		}
+
+		// Repairs may have added, detached or ended nodes
+		if report.NRepaired > 0 {
+			if err = T.countNodes(nodeTree, &report); err != nil {
+				return
+			}
+		}
+	}
+
+	referenced := int64(len(incoming))
+	if !repair {
+		referenced -= int64(len(missing))
+	}
+	report.NUnreferenced = report.NNodes - referenced
+
+	// Counters in the state file are only checked if there is one
+	if _, statErr := os.Stat(T.StateFilename); statErr == nil {
+		if T.NNodes != report.NNodes || T.NUnexpandedNodes != report.NUnexpandedNodes {
+			detail := fmt.Sprintf("state file has %d nodes and %d unexpanded nodes but the node tree %d and %d",
+				T.NNodes, T.NUnexpandedNodes, report.NNodes, report.NUnexpandedNodes)
+			problem := CheckProblem{Kind: ProblemCounters, Position: T.StateFilename, Detail: detail}
+			if repair {
+				T.NNodes, T.NUnexpandedNodes = report.NNodes, report.NUnexpandedNodes
+				if err = T.SaveState(); err != nil {
+					return
+				}
+				problem.Repaired = true
+				report.NRepaired++
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	for _, p := range problems {
+		report.Problems[p.Kind]++
+		fn(p)
+	}
+
+	return
+}
+
+// incomingVisits - Sum of visits of the actions leading to a node and the number of such actions
+type incomingVisits struct {
+	visits   uint64
+	nParents int
+}
+
+// checkChildState - Returns a description of the problem if the node of the action isn't the state given by
+// playing the action in the node, otherwise an empty string. The node key of the state given by playing the action
+// is returned if the action is legal.
+func (T *Tree) checkChildState(node db.MCNode, action db.Action) (problem string, childKey []byte) {
+	if isDone, _ := T.Game.SetState(node.State, node.Player); isDone {
+		return "is played in a finished game", nil
+	}
+
+	if _, _, err := T.Game.Move(action.X, action.Y, action.Pass); err != nil {
+		return fmt.Sprintf("is illegal: %s", err), nil
+	}
+
+	state, player := T.Game.GetState()
+	childKey = db.StateKey(state, player == T.PlayerA)
+	if !bytes.Equal(childKey, action.ActionNodeKey) {
+		childState, childPlayerA := db.KeyState(action.ActionNodeKey)
+		problem = fmt.Sprintf("leads to %s instead of %s",
+			T.Game.FormatPosition(childState, T.player(childPlayerA)), T.Game.FormatPosition(state, player))
+	}
+
+	return
+}
+
+// countNodes - Counts nodes, unexpanded nodes and end nodes of the node tree into the report
+func (T *Tree) countNodes(nodeTree *db.NodeTree, report *CheckReport) error {
+	report.NNodes, report.NUnexpandedNodes, report.NEndNodes = 0, 0, 0
+
+	return nodeTree.ScanNodes(func(nodeKey []byte, node db.MCNode) error {
+		report.NNodes++
+		if node.IsEnd {
+			report.NEndNodes++
+		} else if node.ActionsAddress == math.MaxUint64 {
+			report.NUnexpandedNodes++
+		}
+		return nil
+	})
+}
+
+// player - Returns the player given the player A flag of a node key
+func (T *Tree) player(playerA bool) string {
+	if playerA {
+		return T.PlayerA
+	}
+
+	return T.PlayerB
+}
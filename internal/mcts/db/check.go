@@ -0,0 +1,143 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gostonefire/filehashmap/crt"
+	"io"
+)
+
+// ScanNodes - Calls fn for every node stored in the node tree, including nodes not reachable from the top, in the
+// order of the hash map files. Nodes come without their actions. Returning an error from fn stops the scan.
+func (N *NodeTree) ScanNodes(fn func(nodeKey []byte, mcNode MCNode) error) error {
+	return ScanHashMap(N.Name, func(key, value []byte) error {
+		nodeKey := append([]byte{}, key...)
+
+		return fn(nodeKey, bufferToNode(nodeKey, value, N.playerA, N.playerB))
+	})
+}
+
+// ReadTopActionsRecord - Reads and validates the actions record of the top action, see ReadActionsRecord
+func (N *NodeTree) ReadTopActionsRecord() (actions []Action, err error) {
+	return N.ReadActionsRecord(topActionsAddress)
+}
+
+// ReadActionsRecord - Reads all actions, including hidden ones, of the actions record at actionsAddress while
+// validating it. It returns an error if the record isn't well-formed, i.e. it doesn't fit in the actions file,
+// has no actions or has actions with unknown flags.
+func (N *NodeTree) ReadActionsRecord(actionsAddress uint64) (actions []Action, err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	fileInfo, err := N.ActionsFile.Stat()
+	if err != nil {
+		fmt.Printf("Error while getting size of actions file: %s\n", err)
+		return
+	}
+	size := uint64(fileInfo.Size())
+
+	if actionsAddress < topActionsAddress {
+		return nil, fmt.Errorf("actions address %d is within the header of the actions file", actionsAddress)
+	}
+	if actionsAddress >= size {
+		return nil, fmt.Errorf("actions address %d is beyond end of actions file at %d", actionsAddress, size)
+	}
+
+	buf, err := readFileToBuffer(N.ActionsFile, actionsAddress, io.SeekStart, 1)
+	if err != nil {
+		return
+	}
+	nActions := int(buf[0])
+	if nActions == 0 {
+		return nil, fmt.Errorf("actions record at %d has no actions", actionsAddress)
+	}
+	if actionsAddress+1+uint64(nActions*actionLength) > size {
+		return nil, fmt.Errorf("actions record at %d with %d actions runs beyond end of actions file at %d", actionsAddress, nActions, size)
+	}
+
+	buf, err = readFileToBuffer(N.ActionsFile, 0, io.SeekCurrent, nActions*actionLength)
+	if err != nil {
+		return
+	}
+
+	actions = make([]Action, nActions)
+	for i := 0; i < nActions; i++ {
+		if flags := buf[i*actionLength+int(actionFlagsOffset)]; flags&^(passFlag|hiddenFlag) != 0 {
+			return nil, fmt.Errorf("action %d in actions record at %d has unknown flags %#x", i, actionsAddress, flags)
+		}
+
+		actions[i] = bufferToAction(buf[i*actionLength:])
+		actions[i].ActionNodeKey = append([]byte{}, actions[i].ActionNodeKey...)
+		actions[i].ActionIndex = uint64(i)
+		actions[i].ActionsAddress = actionsAddress
+	}
+
+	return
+}
+
+// HasNode - Returns whether there is a node with the given node key
+func (N *NodeTree) HasNode(nodeKey []byte) (found bool, err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	_, err = N.getValue(nodeKey)
+	if errors.Is(err, crt.NoRecordFound{}) {
+		return false, nil
+	} else if err != nil {
+		fmt.Printf("Error while getting node from file: %s\n", err)
+		return
+	}
+
+	return true, nil
+}
+
+// AddNode - Adds an unexpanded node with the given node key unless it is already present.
+// It returns whether the node was added.
+func (N *NodeTree) AddNode(nodeKey []byte) (added bool, err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	state, player := N.keyToStatePlayer(nodeKey)
+	_, _, reusedNode, err := N.addNode(state, player)
+	if err != nil {
+		fmt.Printf("Error while adding node to file: %s\n", err)
+		return
+	}
+
+	return !reusedNode, nil
+}
+
+// SetNode - Sets the IsEnd flag and the actions address of a node, an actions address of math.MaxUint64 detaches
+// the actions of the node leaving it unexpanded
+func (N *NodeTree) SetNode(nodeKey []byte, isEnd bool, actionsAddress uint64) (err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	mcNode, err := N.getNodeByNodeKey(nodeKey)
+	if err != nil {
+		fmt.Printf("Error while getting node from file: %s\n", err)
+		return
+	}
+	mcNode.IsEnd = isEnd
+	mcNode.ActionsAddress = actionsAddress
+
+	err = N.setValue(nodeKey, nodeToBuffer(mcNode))
+	if err != nil {
+		fmt.Printf("Error while writing node to file: %s\n", err)
+	}
+
+	return
+}
+
+// SetActionNodeKey - Sets the node key of the node an action leads to
+func (N *NodeTree) SetActionNodeKey(actionsAddress, actionIndex uint64, nodeKey []byte) (err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	fileAddress := actionsAddress + 1 + uint64(actionLength)*actionIndex + childNodeKeyOffset
+	if _, err = writeBufferToFile(N.ActionsFile, fileAddress, io.SeekStart, nodeKey[:nodeKeyLength]); err != nil {
+		fmt.Printf("Error while writing node key to action in file\n")
+	}
+
+	return
+}
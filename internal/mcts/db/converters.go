@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -32,7 +33,7 @@ const actionsOffset uint64 = 1
 */
 
 // Total length of one action in file
-const actionLength int = 36
+const actionLength int = 40
 
 // Action offsets
 
@@ -42,18 +43,22 @@ const visitsOffset uint64 = 0        // Needs to be first - 8 bytes
 const pointsOffset uint64 = 8        // Needs to be second - 8 bytes
 const actionXOffset uint64 = 16      // 1 byte
 const actionYOffset uint64 = 17      // 1 byte
-const actionPassOffset uint64 = 18   // 1 byte
+const actionFlagsOffset uint64 = 18  // 1 byte
 const childNodeKeyOffset uint64 = 19 // 17 bytes
+const priorOffset uint64 = 36        // 4 bytes
+
+// Action flags, bits in the byte at actionFlagsOffset
+const passFlag uint8 = 1   // The action is a pass
+const hiddenFlag uint8 = 2 // The action is not yet exposed (progressive widening) and has no node in the node map
 
 /*
 	Visits         8 bytes
 	Points         8 bytes
 	X              1 byte
 	Y              1 byte
-	Pass           1 byte
-	IsDone         1 byte
-	ActionNode 8 byte
-	ActionNodeAddress
+	Flags          1 byte (pass, hidden)
+	ActionNodeKey  17 bytes
+	Prior          4 bytes (float32)
 */
 
 // nodeToBuffer - Converts an MCNode to a byte buffer (value only)
@@ -125,15 +130,21 @@ func actionToBuffer(action Action, buf []byte) {
 	// Action Y in one byte
 	buf[actionYOffset] = action.Y
 
-	// Action Pass in one byte
+	// Action flags in one byte
 	if action.Pass {
-		buf[actionPassOffset] = 1
+		buf[actionFlagsOffset] |= passFlag
+	}
+	if action.Hidden {
+		buf[actionFlagsOffset] |= hiddenFlag
 	}
 
 	// Resulting child node key in file in 17 bytes
 	for i := uint64(0); i < uint64(nodeKeyLength); i++ {
 		buf[childNodeKeyOffset+i] = action.ActionNodeKey[i]
 	}
+
+	// Prior probability in 4 bytes
+	binary.LittleEndian.PutUint32(buf[priorOffset:], math.Float32bits(action.Prior))
 }
 
 // bufferToAction - Converts a byte buffer to an Action
@@ -150,18 +161,24 @@ func bufferToAction(buf []byte) Action {
 	// Action Y in one byte
 	actionY := buf[actionYOffset]
 
-	// Action Pass in one byte
-	actionPass := buf[actionPassOffset] == 1
+	// Action flags in one byte
+	actionPass := buf[actionFlagsOffset]&passFlag != 0
+	actionHidden := buf[actionFlagsOffset]&hiddenFlag != 0
 
 	// Resulting child node key in 17 bytes
 	actionNodeKey := buf[childNodeKeyOffset : childNodeKeyOffset+uint64(nodeKeyLength)]
 
+	// Prior probability in 4 bytes
+	prior := math.Float32frombits(binary.LittleEndian.Uint32(buf[priorOffset:]))
+
 	return Action{
 		Visits:        visits,
 		Points:        points,
 		X:             actionX,
 		Y:             actionY,
 		Pass:          actionPass,
+		Hidden:        actionHidden,
+		Prior:         prior,
 		ActionNodeKey: actionNodeKey,
 	}
 }
@@ -241,3 +258,24 @@ func nodeStateToBuffer(nodeState nodeState) (buf []byte) {
 
 	return
 }
+
+// StateKey - Converts a state and a player flag to a key in the same format as node keys
+func StateKey(state string, playerFlag bool) []byte {
+	stateCodeHigh, stateCodeLow := stateToStateCodes(state)
+
+	return nodeStateToBuffer(nodeState{
+		stateCodeHigh: stateCodeHigh,
+		stateCodeLow:  stateCodeLow,
+		playerA:       playerFlag,
+	})
+}
+
+// KeyState - Converts a key in node key format to a state and a player flag
+func KeyState(key []byte) (state string, playerFlag bool) {
+	state = stateCodesToState(
+		binary.LittleEndian.Uint64(key[stateHighOffset:]),
+		binary.LittleEndian.Uint64(key[stateLowOffset:]),
+	)
+
+	return state, key[playerOffset] == 1
+}
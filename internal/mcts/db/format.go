@@ -0,0 +1,78 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The actions file starts with a header of actionsMagic followed by one byte with the format of the node tree, and
+// the top actions record follows right after the header. Node trees of format 1 have no header, their actions file
+// starts with the top actions record whose first byte is its number of actions, i.e. 1.
+const actionsMagic string = "MCTSACT"
+const actionsHeaderLength int = 8
+const topActionsAddress = uint64(actionsHeaderLength)
+
+// TreeFormat - Format of the node trees written by this version. Format 3 has the prior of each action in its
+// record, making records 40 bytes long instead of 36. Format 2 gives the points of an action to the player that made
+// it and has the opponent of the player that made the last move in turn in final nodes. Format 1 gave the points to
+// the player in turn in the attached node instead, and had the winner in turn in final nodes of tic-tac-toe and
+// vertical four in a row.
+const TreeFormat uint8 = 3
+
+// writeActionsHeader - Writes the header to a new actions file
+func writeActionsHeader(af *os.File) error {
+	_, err := writeBufferToFile(af, 0, io.SeekStart, append([]byte(actionsMagic), TreeFormat))
+
+	return err
+}
+
+// checkActionsHeader - Returns an error unless the actions file starts with a header of the format of this version
+func checkActionsHeader(af *os.File) error {
+	format, err := readActionsFormat(af)
+	if err != nil {
+		return err
+	}
+
+	if format == 1 {
+		fmt.Printf("Error, %s belongs to a node tree of format 1 with points given to the player in turn, relearn it with force new\n", af.Name())
+		return fmt.Errorf("error, %s belongs to a node tree of format 1, relearn it with force new", af.Name())
+	}
+	if format != TreeFormat {
+		fmt.Printf("Error, %s belongs to a node tree of format %d while format %d is supported, relearn it with force new\n", af.Name(), format, TreeFormat)
+		return fmt.Errorf("error, %s belongs to a node tree of format %d while format %d is supported", af.Name(), format, TreeFormat)
+	}
+
+	return nil
+}
+
+// readActionsFormat - Returns the format of the node tree given by the header of its actions file, 1 if there is
+// no header
+func readActionsFormat(af *os.File) (format uint8, err error) {
+	header := make([]byte, actionsHeaderLength)
+	n, err := af.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		fmt.Printf("Error while reading header of %s, %s\n", af.Name(), err)
+		return
+	}
+
+	if n < actionsHeaderLength || string(header[:len(actionsMagic)]) != actionsMagic {
+		return 1, nil
+	}
+
+	return header[len(actionsMagic)], nil
+}
+
+// NodeTreeFormat - Returns the format of the named node tree given by the header of its actions file
+func NodeTreeFormat(nodeTreeName string) (format uint8, err error) {
+	aFile := fmt.Sprintf("%s-actions.bin", nodeTreeName)
+	af, err := os.Open(aFile)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", aFile, err)
+		return
+	}
+	defer func(af *os.File) { _ = af.Close() }(af)
+
+	return readActionsFormat(af)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeTreeFormat(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test")
+
+	nodeTree, err := NewNodeTree(name, "X", "Y", "---------", 1000, true)
+	if err != nil {
+		t.Fatalf("failed to create node tree: %s", err)
+	}
+	nodeTree.Close()
+
+	format, err := NodeTreeFormat(name)
+	if err != nil {
+		t.Fatalf("failed to read format: %s", err)
+	}
+	if format != TreeFormat {
+		t.Errorf("expected format %d, got %d", TreeFormat, format)
+	}
+
+	if nodeTree, err = NewNodeTree(name, "X", "Y", "---------", 1000, false); err != nil {
+		t.Fatalf("failed to open node tree: %s", err)
+	}
+	nodeTree.Close()
+
+	// A node tree of format 1 has no header and starts with the top actions record
+	legacy := make([]byte, 1+actionLength)
+	legacy[0] = 1
+	if err = os.WriteFile(name+"-actions.bin", legacy, 0644); err != nil {
+		t.Fatalf("failed to write actions file: %s", err)
+	}
+
+	if format, err = NodeTreeFormat(name); err != nil {
+		t.Fatalf("failed to read format: %s", err)
+	}
+	if format != 1 {
+		t.Errorf("expected format 1, got %d", format)
+	}
+
+	if _, err = NewNodeTree(name, "X", "Y", "---------", 1000, false); err == nil {
+		t.Errorf("expected a node tree of format 1 to be refused")
+	}
+	if _, err = NewReadOnlyNodeTree(name, "X", "Y"); err == nil {
+		t.Errorf("expected a node tree of format 1 to be refused when opened read only")
+	}
+}
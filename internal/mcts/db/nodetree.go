@@ -9,14 +9,18 @@ import (
 	"io"
 	"math"
 	"os"
+	"sync"
 )
 
 // NodeTree - Struct representing the file based database for a node tree
 type NodeTree struct {
+	Name        string
 	ActionsFile *os.File
 	NodeMap     *filehashmap.FileHashMap
+	reader      *hashMapReader // Set instead of NodeMap when opened read only
 	playerA     string
 	playerB     string
+	mu          sync.Mutex // Serializes file access since seek followed by read or write isn't goroutine safe
 }
 
 // MCNode - Monte Carlo tree search node
@@ -27,6 +31,7 @@ type MCNode struct {
 	Player         string
 	Actions        []Action
 	ActionsAddress uint64
+	NHiddenActions int
 }
 
 // Action - Convenient structure for an action
@@ -36,6 +41,8 @@ type Action struct {
 	X              uint8
 	Y              uint8
 	Pass           bool
+	Hidden         bool
+	Prior          float32
 	ActionNode     MCNode
 	ActionIndex    uint64
 	ActionsAddress uint64
@@ -52,6 +59,11 @@ type nodeState struct {
 
 // NewNodeTree - Creates a new NodeTree either using a new file or existing
 func NewNodeTree(nodeTreeName, playerA, playerB, initialState string, uniqueStates int64, newTree bool) (nodeTree *NodeTree, err error) {
+	// Files of an interrupted resize must be restored before checking which files there are
+	if err = RecoverResize(nodeTreeName); err != nil {
+		return
+	}
+
 	mFile := fmt.Sprintf("%s-map.bin", nodeTreeName)
 	oFile := fmt.Sprintf("%s-ovfl.bin", nodeTreeName)
 	aFile := fmt.Sprintf("%s-actions.bin", nodeTreeName)
@@ -70,12 +82,21 @@ func NewNodeTree(nodeTreeName, playerA, playerB, initialState string, uniqueStat
 		}
 	}
 
-	// Open or create the action file and hash map files
+	// Open or create the action file and hash map files, a new actions file starts with a header telling its format
 	af, err := os.OpenFile(aFile, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		fmt.Printf("Error while open or create %s, %s\n", aFile, err)
 		return nil, err
 	}
+	if newTree {
+		err = writeActionsHeader(af)
+	} else {
+		err = checkActionsHeader(af)
+	}
+	if err != nil {
+		_ = af.Close()
+		return nil, err
+	}
 
 	var fhm *filehashmap.FileHashMap
 	if newTree {
@@ -93,6 +114,7 @@ func NewNodeTree(nodeTreeName, playerA, playerB, initialState string, uniqueStat
 	}
 
 	nt := NodeTree{
+		Name:        nodeTreeName,
 		ActionsFile: af,
 		NodeMap:     fhm,
 		playerA:     playerA,
@@ -119,6 +141,10 @@ func NewNodeTree(nodeTreeName, playerA, playerB, initialState string, uniqueStat
 
 // NewPlayNodeTree - Creates a new NodeTree either using a new file or existing
 func NewPlayNodeTree(nodeTreeName, playerA, playerB, initialState string) (nodeTree *NodeTree, err error) {
+	if err = RecoverResize(nodeTreeName); err != nil {
+		return
+	}
+
 	mFile := fmt.Sprintf("%s-map.bin", nodeTreeName)
 	oFile := fmt.Sprintf("%s-ovfl.bin", nodeTreeName)
 	aFile := fmt.Sprintf("%s-actions.bin", nodeTreeName)
@@ -136,6 +162,10 @@ func NewPlayNodeTree(nodeTreeName, playerA, playerB, initialState string) (nodeT
 		fmt.Printf("Error while open node tree for play: %s\n", err)
 		return nil, err
 	}
+	if err = checkActionsHeader(af); err != nil {
+		_ = af.Close()
+		return nil, err
+	}
 
 	fhm, _, err := filehashmap.NewFromExistingFiles(nodeTreeName, nil)
 	if err != nil {
@@ -144,6 +174,7 @@ func NewPlayNodeTree(nodeTreeName, playerA, playerB, initialState string) (nodeT
 	}
 
 	nt := NodeTree{
+		Name:        nodeTreeName,
 		ActionsFile: af,
 		NodeMap:     fhm,
 		playerA:     playerA,
@@ -154,6 +185,82 @@ func NewPlayNodeTree(nodeTreeName, playerA, playerB, initialState string) (nodeT
 	return
 }
 
+// NewReadOnlyNodeTree - Opens an existing NodeTree read only, no file is ever created or changed and all
+// operations changing the tree return an error
+func NewReadOnlyNodeTree(nodeTreeName, playerA, playerB string) (nodeTree *NodeTree, err error) {
+	if resizeInterrupted(nodeTreeName) {
+		fmt.Printf("Error, resize of %s was interrupted, open it for learning or play to restore its files\n", nodeTreeName)
+		return nil, fmt.Errorf("error, resize of %s was interrupted", nodeTreeName)
+	}
+
+	aFile := fmt.Sprintf("%s-actions.bin", nodeTreeName)
+	for _, file := range []string{fmt.Sprintf("%s-map.bin", nodeTreeName), fmt.Sprintf("%s-ovfl.bin", nodeTreeName), aFile} {
+		if _, err = os.Stat(file); err != nil {
+			fmt.Printf("Error, missing node tree file %s\n", file)
+			return nil, fmt.Errorf("error, missing node tree file %s", file)
+		}
+	}
+
+	af, err := os.Open(aFile)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", aFile, err)
+		return
+	}
+	if err = checkActionsHeader(af); err != nil {
+		_ = af.Close()
+		return
+	}
+
+	reader, err := openHashMapReader(nodeTreeName)
+	if err != nil {
+		_ = af.Close()
+		return
+	}
+
+	nodeTree = &NodeTree{
+		Name:        nodeTreeName,
+		ActionsFile: af,
+		reader:      reader,
+		playerA:     playerA,
+		playerB:     playerB,
+	}
+
+	return
+}
+
+// Close - Closes the node tree files
+func (N *NodeTree) Close() {
+	_ = N.ActionsFile.Close()
+	if N.reader != nil {
+		N.reader.close()
+	} else {
+		N.NodeMap.CloseFiles()
+	}
+}
+
+// ReadOnly - Returns whether the node tree is opened read only
+func (N *NodeTree) ReadOnly() bool {
+	return N.reader != nil
+}
+
+// getValue - Returns the value stored for a node key
+func (N *NodeTree) getValue(nodeKey []byte) ([]byte, error) {
+	if N.reader != nil {
+		return N.reader.Get(nodeKey)
+	}
+
+	return N.NodeMap.Get(nodeKey)
+}
+
+// setValue - Stores the value of a node key unless the node tree is opened read only
+func (N *NodeTree) setValue(nodeKey, value []byte) error {
+	if N.reader != nil {
+		return fmt.Errorf("node tree %s is opened read only", N.Name)
+	}
+
+	return N.NodeMap.Set(nodeKey, value)
+}
+
 // removeExistingFiles - Removes any existing node related files if present
 func removeExistingFiles(files []string) error {
 	for _, file := range files {
@@ -169,31 +276,33 @@ func removeExistingFiles(files []string) error {
 }
 
 // AttachActionNodes - Attaches actions structure in the childrens file and updates the node identified with state
-// accordingly. To each action a child node is created (or identified if already present) and attached.
-// It returns the created children in a slice of Action.
+// accordingly. To each of the first nExposed actions a child node is created (or identified if already present) and
+// attached, the rest are stored as hidden and can be exposed later using ExposeNextAction.
+// It returns the exposed children in a slice of Action.
 func (N *NodeTree) AttachActionNodes(
 	parentState,
 	childPlayer string,
 	actions []Action,
 	actionResultStates []string,
+	nExposed int,
 ) (
 	attachedActions []Action,
 	actionsAddress uint64,
 	nReused int64,
 	err error,
 ) {
-	// Convert states to base3 and create a state key
-	stateCodeHigh, stateCodeLow := stateToStateCodes(parentState)
-	parentStateKey := nodeStateToBuffer(nodeState{
-		stateCodeHigh: stateCodeHigh,
-		stateCodeLow:  stateCodeLow,
-		playerA:       childPlayer != N.playerA,
-	})
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	// Create a state key for the parent
+	parentStateKey := N.nodeKey(parentState, N.opponent(childPlayer))
 
 	nActions := len(actions)
-	attachedActions = make([]Action, nActions)
+	if nExposed > nActions {
+		nExposed = nActions
+	}
 
-	parentValue, err := N.NodeMap.Get(parentStateKey)
+	parentValue, err := N.getValue(parentStateKey)
 	if errors.Is(err, crt.NoRecordFound{}) {
 		fmt.Printf("Error, no such parentState in node registry: %s\n", parentState)
 		err = fmt.Errorf("error, no such parentState in node registry: %s", parentState)
@@ -211,18 +320,24 @@ func (N *NodeTree) AttachActionNodes(
 	for i := 0; i < nActions; i++ {
 		o := 1 + i*actionLength
 
-		resultingChild, actionNodeKey, reusedNode, err = N.addNode(actionResultStates[i], childPlayer)
-		if err != nil {
-			return
-		}
+		if i < nExposed {
+			resultingChild, actionNodeKey, reusedNode, err = N.addNode(actionResultStates[i], childPlayer)
+			if err != nil {
+				return
+			}
 
-		actions[i].ActionNode = resultingChild
-		actions[i].ActionNodeKey = actionNodeKey
-		actionToBuffer(actions[i], buf[o:])
+			actions[i].ActionNode = resultingChild
+			actions[i].ActionNodeKey = actionNodeKey
 
-		if reusedNode {
-			nReused++
+			if reusedNode {
+				nReused++
+			}
+		} else {
+			// Hidden actions only gets the key to their future node
+			actions[i].Hidden = true
+			actions[i].ActionNodeKey = N.nodeKey(actionResultStates[i], childPlayer)
 		}
+		actionToBuffer(actions[i], buf[o:])
 	}
 
 	actionsAddress, err = writeBufferToFile(N.ActionsFile, 0, io.SeekEnd, buf)
@@ -231,7 +346,7 @@ func (N *NodeTree) AttachActionNodes(
 	}
 
 	binary.LittleEndian.PutUint64(parentValue[actionsOffset:], actionsAddress)
-	err = N.NodeMap.Set(parentStateKey, parentValue)
+	err = N.setValue(parentStateKey, parentValue)
 	if err != nil {
 		return
 	}
@@ -241,28 +356,98 @@ func (N *NodeTree) AttachActionNodes(
 		actions[i].ActionsAddress = actionsAddress
 	}
 
-	attachedActions = actions
+	attachedActions = actions[:nExposed]
+
+	return
+}
+
+// ExposeNextAction - Exposes the first hidden action in the actions record at actionsAddress, i.e. creates (or
+// identifies if already present) its child node and clears the hidden flag.
+// It returns the exposed action and whether its node was reused.
+func (N *NodeTree) ExposeNextAction(actionsAddress uint64) (action Action, reusedNode bool, err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	buf, err := readFileToBuffer(N.ActionsFile, actionsAddress, io.SeekStart, 1)
+	if err != nil {
+		return
+	}
+	nActions := int(buf[0])
+
+	buf, err = readFileToBuffer(N.ActionsFile, 0, io.SeekCurrent, nActions*actionLength)
+	if err != nil {
+		return
+	}
+
+	index := -1
+	for i := 0; i < nActions; i++ {
+		if buf[i*actionLength+int(actionFlagsOffset)]&hiddenFlag != 0 {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		fmt.Println("Error, no hidden action left to expose")
+		err = fmt.Errorf("error, no hidden action left to expose")
+		return
+	}
+
+	action = bufferToAction(buf[index*actionLength:])
+	state, player := N.keyToStatePlayer(action.ActionNodeKey)
+	action.ActionNode, _, reusedNode, err = N.addNode(state, player)
+	if err != nil {
+		return
+	}
+	action.Hidden = false
+	action.ActionIndex = uint64(index)
+	action.ActionsAddress = actionsAddress
+
+	// Clear the hidden flag in file
+	flags := []byte{buf[index*actionLength+int(actionFlagsOffset)] &^ hiddenFlag}
+	fileAddress := actionsAddress + 1 + uint64(actionLength*index) + actionFlagsOffset
+	_, err = writeBufferToFile(N.ActionsFile, fileAddress, io.SeekStart, flags)
+	if err != nil {
+		fmt.Printf("Error while writing exposed flag to action in file\n")
+		return
+	}
 
 	return
 }
 
+// nodeKey - Returns the node key for a state and the player in turn
+func (N *NodeTree) nodeKey(state, player string) []byte {
+	return StateKey(state, player == N.playerA)
+}
+
+// keyToStatePlayer - Returns state and player in turn given a node key
+func (N *NodeTree) keyToStatePlayer(nodeKey []byte) (state, player string) {
+	state, playerA := KeyState(nodeKey)
+
+	player = N.playerB
+	if playerA {
+		player = N.playerA
+	}
+
+	return
+}
+
+// opponent - Returns the opponent of the given player
+func (N *NodeTree) opponent(player string) string {
+	if player == N.playerA {
+		return N.playerB
+	}
+
+	return N.playerA
+}
+
 // addNode - Adds a node to the tree.
 // It returns the newly created node and any error
 func (N *NodeTree) addNode(state, player string) (mcNode MCNode, stateKey []byte, reusedNode bool, err error) {
 
-	// Convert states to base3 and create a nodeState instance
-	// stateCode := nodeState{playerA: player == N.playerA}
-	// stateCode.stateCodeHigh, stateCode.stateCodeLow = stateToStateCodes(state)
-
 	// Convert states to base3 and create a state key
-	stateCodeHigh, stateCodeLow := stateToStateCodes(state)
-	stateKey = nodeStateToBuffer(nodeState{
-		stateCodeHigh: stateCodeHigh,
-		stateCodeLow:  stateCodeLow,
-		playerA:       player == N.playerA,
-	})
+	stateKey = N.nodeKey(state, player)
 
-	nodeValue, err := N.NodeMap.Get(stateKey)
+	nodeValue, err := N.getValue(stateKey)
 	if errors.Is(err, crt.NoRecordFound{}) {
 		mcNode = MCNode{
 			Assigned:       true,
@@ -271,7 +456,7 @@ func (N *NodeTree) addNode(state, player string) (mcNode MCNode, stateKey []byte
 			ActionsAddress: math.MaxUint64,
 		}
 		nodeValue = nodeToBuffer(mcNode)
-		err = N.NodeMap.Set(stateKey, nodeValue)
+		err = N.setValue(stateKey, nodeValue)
 		if err != nil {
 			return
 		}
@@ -288,7 +473,10 @@ func (N *NodeTree) addNode(state, player string) (mcNode MCNode, stateKey []byte
 
 // GetTopAction - Returns the top action from the tree
 func (N *NodeTree) GetTopAction() (action Action, err error) {
-	actions, err := N.getActionsByAddress(0)
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	actions, _, err := N.getActionsByAddress(topActionsAddress)
 	if err != nil {
 		return
 	}
@@ -298,7 +486,7 @@ func (N *NodeTree) GetTopAction() (action Action, err error) {
 		return
 	}
 	actions[0].ActionIndex = 0
-	actions[0].ActionsAddress = 0
+	actions[0].ActionsAddress = topActionsAddress
 
 	// Get nodes for associated actions
 	for i := 0; i < len(actions); i++ {
@@ -307,7 +495,7 @@ func (N *NodeTree) GetTopAction() (action Action, err error) {
 			return
 		}
 
-		actions[i].ActionNode.Actions, err = N.getActionsByAddress(actions[i].ActionNode.ActionsAddress)
+		actions[i].ActionNode.Actions, actions[i].ActionNode.NHiddenActions, err = N.getActionsByAddress(actions[i].ActionNode.ActionsAddress)
 		if err != nil {
 			return
 		}
@@ -319,6 +507,9 @@ func (N *NodeTree) GetTopAction() (action Action, err error) {
 
 // GetNode - Retrieves a node given its node key
 func (N *NodeTree) GetNode(nodeKey []byte) (mcNode MCNode, err error) {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
 	// Get node data from file
 	mcNode, err = N.getNodeByNodeKey(nodeKey)
 	if err != nil {
@@ -326,7 +517,7 @@ func (N *NodeTree) GetNode(nodeKey []byte) (mcNode MCNode, err error) {
 	}
 
 	// Get associated actions from file
-	mcNode.Actions, err = N.getActionsByAddress(mcNode.ActionsAddress)
+	mcNode.Actions, mcNode.NHiddenActions, err = N.getActionsByAddress(mcNode.ActionsAddress)
 	if err != nil {
 		return
 	}
@@ -349,10 +540,26 @@ func (N *NodeTree) GetNode(nodeKey []byte) (mcNode MCNode, err error) {
 	return
 }
 
+// IterateNodes - Calls fn for every node stored in the node tree, including nodes not reachable from the top, in
+// the order of the hash map files. Nodes come with their exposed actions. Returning an error from fn stops the
+// iteration.
+func (N *NodeTree) IterateNodes(fn func(nodeKey []byte, mcNode MCNode) error) error {
+	return N.ScanNodes(func(nodeKey []byte, mcNode MCNode) (err error) {
+		N.mu.Lock()
+		mcNode.Actions, mcNode.NHiddenActions, err = N.getActionsByAddress(mcNode.ActionsAddress)
+		N.mu.Unlock()
+		if err != nil {
+			return
+		}
+
+		return fn(nodeKey, mcNode)
+	})
+}
+
 // getNodeByAddress - Retrieves a node given its node key.
 func (N *NodeTree) getNodeByNodeKey(nodeKey []byte) (mcNode MCNode, err error) {
 	// Get node data from file
-	value, err := N.NodeMap.Get(nodeKey)
+	value, err := N.getValue(nodeKey)
 	if err != nil {
 		return
 	}
@@ -365,6 +572,9 @@ func (N *NodeTree) getNodeByNodeKey(nodeKey []byte) (mcNode MCNode, err error) {
 
 // UpdateActionStatistics - Updates visits and points for an action
 func (N *NodeTree) UpdateActionStatistics(actionsAddress, actionIndex, newVisits, newPoints uint64) error {
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
 	// Check for a valid actionsAddress
 	if actionsAddress == math.MaxUint64 {
 		fmt.Println("Error, unassigned actions address provided")
@@ -391,14 +601,17 @@ func (N *NodeTree) UpdateActionStatistics(actionsAddress, actionIndex, newVisits
 
 // SetNodeIsEnd - Marks a node as is end, i.e. there are no more actions to take from that node
 func (N *NodeTree) SetNodeIsEnd(nodeKey []byte) (err error) {
-	value, err := N.NodeMap.Get(nodeKey)
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	value, err := N.getValue(nodeKey)
 	if err != nil {
 		fmt.Printf("Error while setting the IsEnd flag to a node in file: %s\n", err)
 	}
 
 	value[isEndOffset] = 1
 
-	err = N.NodeMap.Set(nodeKey, value)
+	err = N.setValue(nodeKey, value)
 	if err != nil {
 		fmt.Printf("Error while setting the IsEnd flag to a node in file: %s\n", err)
 	}
@@ -406,8 +619,9 @@ func (N *NodeTree) SetNodeIsEnd(nodeKey []byte) (err error) {
 	return
 }
 
-// getActionsByAddress - Retrieves all actions given a file position pointer
-func (N *NodeTree) getActionsByAddress(actionsAddress uint64) (actions []Action, err error) {
+// getActionsByAddress - Retrieves all exposed actions given a file position pointer, and the number of actions
+// still hidden
+func (N *NodeTree) getActionsByAddress(actionsAddress uint64) (actions []Action, nHidden int, err error) {
 	// Check for a valid actionsAddress, otherwise just return
 	if actionsAddress == math.MaxUint64 {
 		return
@@ -424,17 +638,19 @@ func (N *NodeTree) getActionsByAddress(actionsAddress uint64) (actions []Action,
 	// Get index record
 	buf, err = readFileToBuffer(N.ActionsFile, 0, io.SeekCurrent, nActions*actionLength)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	actions = make([]Action, nActions)
+	actions = make([]Action, 0, nActions)
 	for i := 0; i < nActions; i++ {
-		actions[i] = bufferToAction(buf[i*actionLength:])
-		actions[i].ActionIndex = uint64(i)
-		actions[i].ActionsAddress = actionsAddress
-		if err != nil {
-			return
+		action := bufferToAction(buf[i*actionLength:])
+		if action.Hidden {
+			nHidden++
+			continue
 		}
+		action.ActionIndex = uint64(i)
+		action.ActionsAddress = actionsAddress
+		actions = append(actions, action)
 	}
 	return
 }
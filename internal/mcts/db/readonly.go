@@ -0,0 +1,103 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/gostonefire/filehashmap/crt"
+	"hash/crc32"
+	"os"
+)
+
+// hashMapReader - Looks up records in the separate chaining files of a file hash map opened read only. The file
+// hash map itself always opens its files for writing, so a read only node tree reads the files directly (see
+// ScanHashMap for the file format). Buckets are selected as crc32 of the key modulo the number of buckets, which
+// is a power of two.
+type hashMapReader struct {
+	mapFile      *os.File
+	ovflFile     *os.File
+	header       HashMapHeader
+	recordLength int64
+}
+
+// openHashMapReader - Opens the files of the named file hash map read only
+func openHashMapReader(name string) (reader *hashMapReader, err error) {
+	header, err := ReadHashMapHeader(name)
+	if err != nil {
+		return
+	}
+
+	mFile := fmt.Sprintf("%s-map.bin", name)
+	mf, err := os.Open(mFile)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", mFile, err)
+		return
+	}
+
+	oFile := fmt.Sprintf("%s-ovfl.bin", name)
+	of, err := os.Open(oFile)
+	if err != nil {
+		_ = mf.Close()
+		fmt.Printf("Error while open %s, %s\n", oFile, err)
+		return
+	}
+
+	reader = &hashMapReader{
+		mapFile:      mf,
+		ovflFile:     of,
+		header:       header,
+		recordLength: 1 + header.KeyLength + header.ValueLength,
+	}
+
+	return
+}
+
+// Get - Returns the value of the record with the given key, or crt.NoRecordFound if there is none
+func (H *hashMapReader) Get(key []byte) (value []byte, err error) {
+	if int64(len(key)) != H.header.KeyLength {
+		return nil, fmt.Errorf("wrong length of key, should be %d", H.header.KeyLength)
+	}
+
+	// Records in the bucket
+	bucketNo := int64(crc32.ChecksumIEEE(key)) & (H.header.Buckets - 1)
+	bucketLength := hashMapBucketHeaderLength + H.recordLength*H.header.RecordsPerBucket
+	buf := make([]byte, bucketLength)
+	if _, err = H.mapFile.ReadAt(buf, hashMapHeaderLength+bucketNo*bucketLength); err != nil {
+		return nil, fmt.Errorf("error while reading bucket %d: %s", bucketNo, err)
+	}
+	for o := hashMapBucketHeaderLength; o < bucketLength; o += H.recordLength {
+		if value, found := H.match(buf[o:o+H.recordLength], key); found {
+			return value, nil
+		}
+	}
+
+	// Records in the overflow chain of the bucket, an address of zero ends the chain
+	address := int64(binary.LittleEndian.Uint64(buf))
+	buf = make([]byte, hashMapOverflowAddressLength+H.recordLength)
+	for address != 0 {
+		if _, err = H.ovflFile.ReadAt(buf, address); err != nil {
+			return nil, fmt.Errorf("error while reading overflow record at %d: %s", address, err)
+		}
+		if value, found := H.match(buf[hashMapOverflowAddressLength:], key); found {
+			return value, nil
+		}
+		address = int64(binary.LittleEndian.Uint64(buf))
+	}
+
+	return nil, crt.NoRecordFound{}
+}
+
+// match - Returns a copy of the value of a record if it is occupied and has the given key
+func (H *hashMapReader) match(record, key []byte) (value []byte, found bool) {
+	if record[0] != hashMapRecordOccupied || !bytes.Equal(record[1:1+H.header.KeyLength], key) {
+		return nil, false
+	}
+
+	return append([]byte(nil), record[1+H.header.KeyLength:H.recordLength]...), true
+}
+
+// close - Closes the files
+func (H *hashMapReader) close() {
+	_ = H.mapFile.Close()
+	_ = H.ovflFile.Close()
+}
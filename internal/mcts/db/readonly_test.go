@@ -0,0 +1,54 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gostonefire/filehashmap"
+	"github.com/gostonefire/filehashmap/crt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashMapReader(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test")
+
+	// Few buckets make most records end up in overflow chains
+	fhm, _, err := filehashmap.NewFileHashMap(name, crt.SeparateChaining, 16, 2, 17, 9, nil)
+	if err != nil {
+		t.Fatalf("failed to create file hash map: %s", err)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	records := make(map[string][]byte)
+	for i := 0; i < 500; i++ {
+		key, value := make([]byte, 17), make([]byte, 9)
+		rnd.Read(key)
+		rnd.Read(value)
+		if err = fhm.Set(key, value); err != nil {
+			t.Fatalf("failed to set record: %s", err)
+		}
+		records[string(key)] = value
+	}
+	fhm.CloseFiles()
+
+	reader, err := openHashMapReader(name)
+	if err != nil {
+		t.Fatalf("failed to open reader: %s", err)
+	}
+	defer reader.close()
+
+	for key, value := range records {
+		got, err := reader.Get([]byte(key))
+		if err != nil {
+			t.Fatalf("failed to get record: %s", err)
+		}
+		if !bytes.Equal(got, value) {
+			t.Errorf("expected value %v, got %v", value, got)
+		}
+	}
+
+	if _, err = reader.Get(make([]byte, 17)); !errors.Is(err, crt.NoRecordFound{}) {
+		t.Errorf("expected no record found for missing key, got %v", err)
+	}
+}
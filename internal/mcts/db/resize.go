@@ -0,0 +1,201 @@
+package db
+
+import (
+	"fmt"
+	"github.com/gostonefire/filehashmap"
+	"os"
+)
+
+// HashMapUsage - Cheaply obtained usage of the node hash map. Nodes are never removed from the map, hence every
+// record in the overflow file is in use and their number is given by the size of the file.
+type HashMapUsage struct {
+	Buckets          int64
+	RecordsPerBucket int64
+	OverflowRecords  int64
+}
+
+// LoadFactor - Returns records per available record slot in the map file given the number of records
+func (H HashMapUsage) LoadFactor(records int64) float64 {
+	if slots := H.Buckets * H.RecordsPerBucket; slots > 0 {
+		return float64(records) / float64(slots)
+	}
+
+	return 0
+}
+
+// OverflowShare - Returns the share of records in the overflow file given the number of records
+func (H HashMapUsage) OverflowShare(records int64) float64 {
+	if records > 0 {
+		return float64(H.OverflowRecords) / float64(records)
+	}
+
+	return 0
+}
+
+// HashMapUsage - Returns usage of the node hash map without scanning it
+func (N *NodeTree) HashMapUsage() (usage HashMapUsage, err error) {
+	header, err := ReadHashMapHeader(N.Name)
+	if err != nil {
+		return
+	}
+
+	oFile := fmt.Sprintf("%s-ovfl.bin", N.Name)
+	fileInfo, err := os.Stat(oFile)
+	if err != nil {
+		fmt.Printf("Error while stat %s, %s\n", oFile, err)
+		return
+	}
+
+	usage = HashMapUsage{Buckets: header.Buckets, RecordsPerBucket: header.RecordsPerBucket}
+	if size := fileInfo.Size() - hashMapHeaderLength; size > 0 {
+		usage.OverflowRecords = size / (hashMapOverflowAddressLength + 1 + header.KeyLength + header.ValueLength)
+	}
+
+	return
+}
+
+// Resize - Rehashes the node hash map into a new map with room for the given number of unique states. The map files
+// are closed while rehashing and reopened afterwards, also if rehashing failed and the old files were restored, but
+// not if the files are left in a half swapped state.
+func (N *NodeTree) Resize(uniqueStates int64) (err error) {
+	if N.ReadOnly() {
+		fmt.Printf("Error, node tree %s is opened read only\n", N.Name)
+		return fmt.Errorf("error, node tree %s is opened read only", N.Name)
+	}
+
+	N.mu.Lock()
+	defer N.mu.Unlock()
+
+	N.NodeMap.CloseFiles()
+	err = ResizeHashMap(N.Name, uniqueStates)
+
+	if resizeInterrupted(N.Name) {
+		fmt.Printf("Error, resize of %s left the files half swapped, they are restored when the tree is opened again\n", N.Name)
+		return fmt.Errorf("error, resize of %s left the files half swapped", N.Name)
+	}
+
+	fhm, _, openErr := filehashmap.NewFromExistingFiles(N.Name, nil)
+	if openErr != nil {
+		fmt.Printf("Error while opening FileHashMap, %s\n", openErr)
+		return openErr
+	}
+	N.NodeMap = fhm
+
+	return
+}
+
+// resizeFiles - Returns the files taking part in swapping the hash map files of a resize, pairs of the new file, the
+// file in use and the backup of the file in use
+func resizeFiles(name string) [][3]string {
+	return [][3]string{
+		{fmt.Sprintf("%s-reorg-map.bin", name), fmt.Sprintf("%s-map.bin", name), fmt.Sprintf("%s-map.bak", name)},
+		{fmt.Sprintf("%s-reorg-ovfl.bin", name), fmt.Sprintf("%s-ovfl.bin", name), fmt.Sprintf("%s-ovfl.bak", name)},
+	}
+}
+
+// resizeMarker - Returns the name of the marker file present while hash map files are swapped
+func resizeMarker(name string) string {
+	return fmt.Sprintf("%s-resize.marker", name)
+}
+
+// resizeInterrupted - Returns whether a swap of hash map files of the named node tree hasn't completed
+func resizeInterrupted(name string) bool {
+	_, err := os.Stat(resizeMarker(name))
+	return err == nil
+}
+
+// ResizeHashMap - Rehashes the named node hash map, which must not be open, into a new map with room for the given
+// number of unique states. The new map is built in -reorg files that replace the old files once complete. The old
+// files are first renamed to backups, with a marker file present until all files are in place, so that a swap that
+// fails is rolled back and one that is interrupted is rolled back by RecoverResize.
+func ResizeHashMap(name string, uniqueStates int64) (err error) {
+	if err = RecoverResize(name); err != nil {
+		return
+	}
+
+	files := resizeFiles(name)
+
+	// Records per bucket must be given since reorganizing otherwise falls back to one record per bucket
+	header, err := ReadHashMapHeader(name)
+	if err != nil {
+		return
+	}
+	reorgConf := filehashmap.ReorgConf{NumberOfBucketsNeeded: int(uniqueStates), RecordsPerBucket: int(header.RecordsPerBucket)}
+
+	// Remove leftovers from an earlier resize that didn't complete
+	if err = removeExistingFiles([]string{files[0][0], files[1][0]}); err != nil {
+		fmt.Println("Error while trying to remove existing reorg files")
+		return
+	}
+
+	_, _, err = filehashmap.ReorgFiles(name, reorgConf, true)
+	if err != nil {
+		fmt.Printf("Error while rehashing FileHashMap, %s\n", err)
+		return
+	}
+
+	// From here on the files in use are only restored from backups until the marker is removed
+	if err = os.WriteFile(resizeMarker(name), []byte("resize in progress\n"), 0644); err != nil {
+		fmt.Printf("Error while writing %s, %s\n", resizeMarker(name), err)
+		return
+	}
+
+	for _, f := range files {
+		if err = os.Rename(f[1], f[2]); err != nil {
+			fmt.Printf("Error while backing up %s, %s\n", f[1], err)
+			_ = RecoverResize(name)
+			return
+		}
+	}
+	for _, f := range files {
+		if err = os.Rename(f[0], f[1]); err != nil {
+			fmt.Printf("Error while replacing %s, %s\n", f[1], err)
+			_ = RecoverResize(name)
+			return
+		}
+	}
+
+	// Removing the marker completes the swap, backups are then no longer needed
+	if err = os.Remove(resizeMarker(name)); err != nil {
+		fmt.Printf("Error while removing %s, %s\n", resizeMarker(name), err)
+		_ = RecoverResize(name)
+		return
+	}
+	if err = removeExistingFiles([]string{files[0][2], files[1][2]}); err != nil {
+		fmt.Println("Error while trying to remove backups of resized files")
+	}
+
+	return
+}
+
+// RecoverResize - Rolls back a swap of hash map files of the named node tree that didn't complete, i.e. restores
+// the files in use from their backups and removes the new files and the marker. Nothing is done unless the marker
+// is present.
+func RecoverResize(name string) (err error) {
+	if !resizeInterrupted(name) {
+		return
+	}
+
+	fmt.Printf("Restoring hash map files of %s from an interrupted resize\n", name)
+	for _, f := range resizeFiles(name) {
+		if _, statErr := os.Stat(f[2]); statErr != nil {
+			continue
+		}
+		if err = os.Rename(f[2], f[1]); err != nil {
+			fmt.Printf("Error while restoring %s, %s\n", f[1], err)
+			return
+		}
+	}
+
+	files := resizeFiles(name)
+	if err = removeExistingFiles([]string{files[0][0], files[1][0]}); err != nil {
+		fmt.Println("Error while trying to remove reorg files")
+		return
+	}
+
+	if err = os.Remove(resizeMarker(name)); err != nil {
+		fmt.Printf("Error while removing %s, %s\n", resizeMarker(name), err)
+	}
+
+	return
+}
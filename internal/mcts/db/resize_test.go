@@ -0,0 +1,119 @@
+package db
+
+import (
+	"bytes"
+	"github.com/gostonefire/filehashmap"
+	"github.com/gostonefire/filehashmap/crt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestHashMap - Creates a file hash map with random records laid out as the node hash map
+func newTestHashMap(t *testing.T, name string, buckets, nRecords int) map[string][]byte {
+	fhm, _, err := filehashmap.NewFileHashMap(name, crt.SeparateChaining, buckets, 2, 17, 9, nil)
+	if err != nil {
+		t.Fatalf("failed to create file hash map: %s", err)
+	}
+	defer fhm.CloseFiles()
+
+	rnd := rand.New(rand.NewSource(int64(nRecords)))
+	records := make(map[string][]byte)
+	for i := 0; i < nRecords; i++ {
+		key, value := make([]byte, 17), make([]byte, 9)
+		rnd.Read(key)
+		rnd.Read(value)
+		if err = fhm.Set(key, value); err != nil {
+			t.Fatalf("failed to set record: %s", err)
+		}
+		records[string(key)] = value
+	}
+
+	return records
+}
+
+// checkRecords - Checks that the named file hash map holds exactly the given records
+func checkRecords(t *testing.T, name string, records map[string][]byte) {
+	reader, err := openHashMapReader(name)
+	if err != nil {
+		t.Fatalf("failed to open reader: %s", err)
+	}
+	defer reader.close()
+
+	for key, value := range records {
+		got, err := reader.Get([]byte(key))
+		if err != nil {
+			t.Fatalf("failed to get record: %s", err)
+		}
+		if !bytes.Equal(got, value) {
+			t.Fatalf("expected value %v, got %v", value, got)
+		}
+	}
+
+	var n int
+	if err = ScanHashMap(name, func(_, _ []byte) error { n++; return nil }); err != nil {
+		t.Fatalf("failed to scan: %s", err)
+	}
+	if n != len(records) {
+		t.Errorf("expected %d records, got %d", len(records), n)
+	}
+}
+
+func TestResizeHashMap(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test")
+	records := newTestHashMap(t, name, 16, 500)
+
+	if err := ResizeHashMap(name, 1024); err != nil {
+		t.Fatalf("failed to resize: %s", err)
+	}
+
+	header, err := ReadHashMapHeader(name)
+	if err != nil {
+		t.Fatalf("failed to read header: %s", err)
+	}
+	if header.Buckets != 1024 || header.RecordsPerBucket != 2 {
+		t.Errorf("expected 1024 buckets of 2 records, got %d of %d", header.Buckets, header.RecordsPerBucket)
+	}
+	checkRecords(t, name, records)
+
+	files := resizeFiles(name)
+	for _, file := range []string{files[0][0], files[0][2], files[1][0], files[1][2], resizeMarker(name)} {
+		if _, err = os.Stat(file); err == nil {
+			t.Errorf("expected %s to be removed", file)
+		}
+	}
+}
+
+func TestRecoverResize(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test")
+	records := newTestHashMap(t, name, 16, 500)
+
+	// A resize interrupted after the map file was swapped but before the overflow file was
+	newTestHashMap(t, filepath.Join(dir, "test-reorg"), 1024, 10)
+	files := resizeFiles(name)
+	if err := os.WriteFile(resizeMarker(name), nil, 0644); err != nil {
+		t.Fatalf("failed to write marker: %s", err)
+	}
+	for _, f := range files {
+		if err := os.Rename(f[1], f[2]); err != nil {
+			t.Fatalf("failed to back up: %s", err)
+		}
+	}
+	if err := os.Rename(files[0][0], files[0][1]); err != nil {
+		t.Fatalf("failed to swap: %s", err)
+	}
+
+	if _, err := NewReadOnlyNodeTree(name, "A", "B"); err == nil {
+		t.Errorf("expected read only open of a half swapped map to fail")
+	}
+
+	if err := RecoverResize(name); err != nil {
+		t.Fatalf("failed to recover: %s", err)
+	}
+	if resizeInterrupted(name) {
+		t.Errorf("expected marker to be removed")
+	}
+	checkRecords(t, name, records)
+}
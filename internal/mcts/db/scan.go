@@ -0,0 +1,136 @@
+package db
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/gostonefire/filehashmap/crt"
+	"io"
+	"os"
+)
+
+/*
+	The file hash map has no way of iterating over its records, so ScanHashMap reads the separate chaining file format
+	of filehashmap directly. The map file starts with a 1024 bytes header followed by fixed size buckets, each with an
+	8 bytes overflow address and then the records of the bucket. The overflow file starts with a 1024 bytes header
+	followed by records appended as they overflowed, each with an 8 bytes address of the next record in the chain.
+	Every record starts with a state byte (0 empty, 1 occupied, 2 deleted) followed by the key and the value.
+*/
+
+// File hash map header offsets and lengths
+const hashMapHeaderLength int64 = 1024
+const hashMapKeyLengthOffset int64 = 1
+const hashMapValueLengthOffset int64 = 5
+const hashMapBucketsAvailableOffset int64 = 17
+const hashMapRecordsPerBucketOffset int64 = 25
+const hashMapCRTOffset int64 = 49
+
+// File hash map bucket and record layout
+const hashMapBucketHeaderLength int64 = 8
+const hashMapOverflowAddressLength int64 = 8
+const hashMapRecordOccupied uint8 = 1
+
+// HashMapHeader - Parts of a file hash map header needed to scan the files
+type HashMapHeader struct {
+	KeyLength        int64
+	ValueLength      int64
+	Buckets          int64
+	RecordsPerBucket int64
+}
+
+// ReadHashMapHeader - Reads the header of the map file of the named file hash map
+func ReadHashMapHeader(name string) (header HashMapHeader, err error) {
+	mFile := fmt.Sprintf("%s-map.bin", name)
+	f, err := os.Open(mFile)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", mFile, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	buf := make([]byte, hashMapHeaderLength)
+	if _, err = io.ReadFull(f, buf); err != nil {
+		fmt.Printf("Error while reading header of %s, %s\n", mFile, err)
+		return
+	}
+
+	if int(buf[hashMapCRTOffset]) != crt.SeparateChaining {
+		fmt.Printf("Error, %s isn't using separate chaining\n", mFile)
+		return header, fmt.Errorf("error, %s isn't using separate chaining", mFile)
+	}
+
+	header = HashMapHeader{
+		KeyLength:        int64(binary.LittleEndian.Uint32(buf[hashMapKeyLengthOffset:])),
+		ValueLength:      int64(binary.LittleEndian.Uint32(buf[hashMapValueLengthOffset:])),
+		Buckets:          int64(binary.LittleEndian.Uint64(buf[hashMapBucketsAvailableOffset:])),
+		RecordsPerBucket: int64(binary.LittleEndian.Uint64(buf[hashMapRecordsPerBucketOffset:])),
+	}
+
+	return
+}
+
+// ScanHashMap - Calls fn with key and value of every occupied record in the named file hash map, first the records
+// of the map file in bucket order and then the records of the overflow file in file order. Returning an error from
+// fn stops the scan. Key and value are only valid during the call.
+func ScanHashMap(name string, fn func(key, value []byte) error) (err error) {
+	header, err := ReadHashMapHeader(name)
+	if err != nil {
+		return
+	}
+	recordLength := 1 + header.KeyLength + header.ValueLength
+
+	// Records in the map file
+	err = scanFile(fmt.Sprintf("%s-map.bin", name), hashMapBucketHeaderLength+recordLength*header.RecordsPerBucket, func(buf []byte) error {
+		for o := hashMapBucketHeaderLength; o < int64(len(buf)); o += recordLength {
+			if buf[o] == hashMapRecordOccupied {
+				if err := fn(buf[o+1:o+1+header.KeyLength], buf[o+1+header.KeyLength:o+recordLength]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// Records in the overflow file
+	return scanFile(fmt.Sprintf("%s-ovfl.bin", name), hashMapOverflowAddressLength+recordLength, func(buf []byte) error {
+		o := hashMapOverflowAddressLength
+		if buf[o] == hashMapRecordOccupied {
+			return fn(buf[o+1:o+1+header.KeyLength], buf[o+1+header.KeyLength:o+recordLength])
+		}
+		return nil
+	})
+}
+
+// scanFile - Reads a file hash map file block by block after its header and calls fn for each block
+func scanFile(fileName string, blockLength int64, fn func(buf []byte) error) (err error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	if _, err = f.Seek(hashMapHeaderLength, io.SeekStart); err != nil {
+		fmt.Printf("Error while seeking in %s, %s\n", fileName, err)
+		return
+	}
+
+	r := bufio.NewReaderSize(f, 1<<20)
+	buf := make([]byte, blockLength)
+	for {
+		if _, err = io.ReadFull(r, buf); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			fmt.Printf("Error while reading %s, %s\n", fileName, err)
+			return
+		}
+
+		if err = fn(buf); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package db
+
+import "fmt"
+
+// HashMapStats - Usage of the node hash map, the load factor is records per available record slot in the map file
+type HashMapStats struct {
+	Buckets          int64   `json:"buckets"`
+	RecordsPerBucket int64   `json:"recordsPerBucket"`
+	Records          int     `json:"records"`
+	MapFileRecords   int     `json:"mapFileRecords"`
+	OverflowRecords  int     `json:"overflowRecords"`
+	LoadFactor       float64 `json:"loadFactor"`
+}
+
+// HashMapStats - Returns usage statistics of the node hash map
+func (N *NodeTree) HashMapStats() (stats HashMapStats, err error) {
+	header, err := ReadHashMapHeader(N.Name)
+	if err != nil {
+		return
+	}
+
+	stats = HashMapStats{Buckets: header.Buckets, RecordsPerBucket: header.RecordsPerBucket}
+	if N.ReadOnly() {
+		// The file hash map isn't open, count records in the files instead
+		var usage HashMapUsage
+		if usage, err = N.HashMapUsage(); err != nil {
+			return
+		}
+		if err = ScanHashMap(N.Name, func(_, _ []byte) error { stats.Records++; return nil }); err != nil {
+			return
+		}
+		stats.OverflowRecords = int(usage.OverflowRecords)
+		stats.MapFileRecords = stats.Records - stats.OverflowRecords
+	} else {
+		N.mu.Lock()
+		hashMapStat, statErr := N.NodeMap.Stat(false)
+		N.mu.Unlock()
+		if statErr != nil {
+			fmt.Printf("Error while getting hash map statistics: %s\n", statErr)
+			return stats, statErr
+		}
+		stats.Records = hashMapStat.Records
+		stats.MapFileRecords = hashMapStat.MapFileRecords
+		stats.OverflowRecords = hashMapStat.OverflowRecords
+	}
+
+	if slots := header.Buckets * header.RecordsPerBucket; slots > 0 {
+		stats.LoadFactor = float64(stats.Records) / float64(slots)
+	}
+
+	return
+}
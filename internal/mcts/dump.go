@@ -0,0 +1,167 @@
+package mcts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"strings"
+)
+
+// Dump formats
+const (
+	TextDump int = iota
+	JSONDump
+)
+
+// DumpFilter - Selects which nodes to dump. Depth is counted from the top node at depth 1, zero values means no
+// limit. If Position is given only that node is dumped, in the notation of the game or as a base 3 state and player.
+type DumpFilter struct {
+	MinDepth int
+	MaxDepth int
+	Position string
+	MaxNodes int
+}
+
+// DumpRecord - One dumped node. Depth is only given when filtering by depth, nodes not reachable from the top are
+// then left out.
+type DumpRecord struct {
+	State          string       `json:"state"`
+	Player         string       `json:"player"`
+	Position       string       `json:"position"`
+	Depth          int          `json:"depth,omitempty"`
+	IsEnd          bool         `json:"isEnd"`
+	Expanded       bool         `json:"expanded"`
+	ActionsAddress uint64       `json:"actionsAddress"`
+	HiddenActions  int          `json:"hiddenActions"`
+	Actions        []DumpAction `json:"actions"`
+}
+
+// DumpAction - One exposed action of a dumped node, value is for the player making the move
+type DumpAction struct {
+	Move   string  `json:"move"`
+	Visits uint64  `json:"visits"`
+	Points uint64  `json:"points"`
+	Value  float64 `json:"value"`
+	Prior  float32 `json:"prior"`
+	Child  string  `json:"child"`
+}
+
+// DumpNodes - Calls fn with every node in the node tree passing the filter, in the order of the node tree files
+func (T *Tree) DumpNodes(filter DumpFilter, fn func(record DumpRecord) error) (err error) {
+	if filter.Position != "" {
+		var state, player string
+		if state, player, err = T.ParsePosition(filter.Position); err != nil {
+			return
+		}
+
+		var node db.MCNode
+		if node, err = T.NodeDB.GetNode(db.StateKey(state, player == T.PlayerA)); err != nil {
+			fmt.Printf("Error, position not found in the tree: %s\n", err)
+			return
+		}
+
+		return fn(T.dumpRecord(node, 0))
+	}
+
+	var depths map[string]int
+	if filter.MinDepth > 0 || filter.MaxDepth > 0 {
+		if depths, err = NodeDepths(T.NodeDB); err != nil {
+			return
+		}
+	}
+
+	var nDumped int
+	errDone := errors.New("done")
+	err = T.NodeDB.IterateNodes(func(nodeKey []byte, mcNode db.MCNode) error {
+		var depth int
+		if depths != nil {
+			var reachable bool
+			depth, reachable = depths[string(nodeKey)]
+			if !reachable || depth < filter.MinDepth || filter.MaxDepth > 0 && depth > filter.MaxDepth {
+				return nil
+			}
+		}
+
+		if err := fn(T.dumpRecord(mcNode, depth)); err != nil {
+			return err
+		}
+
+		nDumped++
+		if filter.MaxNodes > 0 && nDumped >= filter.MaxNodes {
+			return errDone
+		}
+		return nil
+	})
+	if errors.Is(err, errDone) {
+		err = nil
+	}
+
+	return
+}
+
+// dumpRecord - Returns the dump record of a node
+func (T *Tree) dumpRecord(node db.MCNode, depth int) DumpRecord {
+	record := DumpRecord{
+		State:          node.State,
+		Player:         node.Player,
+		Position:       T.Game.FormatPosition(node.State, node.Player),
+		Depth:          depth,
+		IsEnd:          node.IsEnd,
+		Expanded:       node.Actions != nil,
+		ActionsAddress: node.ActionsAddress,
+		HiddenActions:  node.NHiddenActions,
+		Actions:        make([]DumpAction, 0, len(node.Actions)),
+	}
+
+	for _, a := range node.Actions {
+		childState, childPlayerA := db.KeyState(a.ActionNodeKey)
+		childPlayer := T.PlayerB
+		if childPlayerA {
+			childPlayer = T.PlayerA
+		}
+
+		dumpAction := DumpAction{
+			Move:   FormatMove(Action{X: a.X, Y: a.Y, Pass: a.Pass}),
+			Visits: a.Visits,
+			Points: a.Points,
+			Prior:  a.Prior,
+			Child:  T.Game.FormatPosition(childState, childPlayer),
+		}
+		if a.Visits > 0 {
+			dumpAction.Value = float64(a.Points) / 2 / float64(a.Visits)
+		}
+		record.Actions = append(record.Actions, dumpAction)
+	}
+
+	return record
+}
+
+// RenderBoard - Renders the board of a position in notation of the game with row numbers and column letters, in
+// the same layout as the games print their boards
+func RenderBoard(position string) string {
+	rowsPart, _, _ := strings.Cut(position, " ")
+	rows := strings.Split(rowsPart, "/")
+
+	var b strings.Builder
+	for i, row := range rows {
+		fmt.Fprintf(&b, "%d ", len(rows)-i)
+		for _, c := range row {
+			marker := " "
+			if c != '.' {
+				marker = string(c)
+			}
+			fmt.Fprintf(&b, "|%s", marker)
+		}
+		b.WriteString("|\n")
+	}
+
+	if len(rows) > 0 {
+		b.WriteString("  ")
+		for c := 0; c < len(rows[0]); c++ {
+			fmt.Fprintf(&b, " %c", 'A'+c)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
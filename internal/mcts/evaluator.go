@@ -0,0 +1,46 @@
+package mcts
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+)
+
+// Leaf evaluators of states at the playout cutoff, the heuristic one is the evaluation of the game if it implements
+// Evaluator
+const (
+	HeuristicLeafEvaluator = conf.HeuristicLeafEvaluator
+	NetworkLeafEvaluator   = conf.NetworkLeafEvaluator
+)
+
+// Evaluator - Optional interface for a BoardGame that can statically evaluate its current state. When the game
+// implements it, and a playout cutoff is set on the tree, Simulate stops after that many plies and uses the
+// evaluation instead of playing to the end of the game.
+type Evaluator interface {
+	Evaluate() float64 // Returns: Estimated probability (0 to 1) that the player in turn wins
+}
+
+// WinnerResult - Converts a winner to a result as seen from PlayerA, 1 is a win for PlayerA, 0 is a win for
+// PlayerB and 0.5 is a draw (empty string)
+func (T *Tree) WinnerResult(winner string) float64 {
+	return resultFor(T.PlayerA, winner)
+}
+
+// resultWinner - Converts a result as seen from PlayerA to a winner. Estimated results (i.e. values in between)
+// are given to the player being favoured, and an even estimation is considered a draw.
+func (T *Tree) resultWinner(result float64) string {
+	if result > 0.5 {
+		return T.PlayerA
+	} else if result < 0.5 {
+		return T.PlayerB
+	}
+
+	return ""
+}
+
+// resultForPlayer - Converts a result as seen from PlayerA to a result as seen from the given player
+func (T *Tree) resultForPlayer(player string, result float64) float64 {
+	if player == T.PlayerA {
+		return result
+	}
+
+	return 1 - result
+}
@@ -0,0 +1,208 @@
+package mcts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/nn"
+	"os"
+	"strings"
+)
+
+// Export formats
+const (
+	JSONLExport int = iota
+	NpyExport
+)
+
+// ExportFilter - Selects which nodes to export. Depth is counted from the top node at depth 1 and game phase is
+// given as number of markers on the board, zero max values means no limit.
+type ExportFilter struct {
+	MinVisits  uint64
+	MinDepth   int
+	MaxDepth   int
+	MinMarkers int
+	MaxMarkers int
+}
+
+// ExportRecord - One exported node, values are for the player in turn
+type ExportRecord struct {
+	State   string         `json:"state"`
+	Player  string         `json:"player"`
+	Depth   int            `json:"depth"`
+	Visits  uint64         `json:"visits"`
+	Value   float64        `json:"value"`
+	Planes  []float64      `json:"planes"`
+	Policy  []float64      `json:"policy"`
+	Actions []ExportAction `json:"actions"`
+}
+
+// ExportAction - Statistics for one action of an exported node
+type ExportAction struct {
+	X      uint8   `json:"x"`
+	Y      uint8   `json:"y"`
+	Pass   bool    `json:"pass"`
+	Visits uint64  `json:"visits"`
+	Value  float64 `json:"value"`
+}
+
+// ExportWriter - Interface for writers of exported records
+type ExportWriter interface {
+	Write(record ExportRecord) error
+	Close() error
+}
+
+// ExportRecords - Walks the node tree and calls fn with every node passing the filter. Planes and policy are
+// encoded as for the network, see BoardPlanes and PolicyIndex.
+func (T *Tree) ExportRecords(filter ExportFilter, fn func(record ExportRecord) error) error {
+	nPositions := boardPositions(T.Game)
+	_, rows := T.Game.BoardSize()
+
+	return WalkTree(T.NodeDB, filter.MinVisits, filter.MaxDepth, func(action db.Action, depth int) error {
+		if action.Visits == 0 || depth < filter.MinDepth {
+			return nil
+		}
+
+		markers := len(strings.ReplaceAll(action.ActionNode.State, "0", ""))
+		if markers < filter.MinMarkers || filter.MaxMarkers > 0 && markers > filter.MaxMarkers {
+			return nil
+		}
+
+		// Points on the action are given to the player that made it, i.e. the opponent of the player in turn
+		record := ExportRecord{
+			State:   action.ActionNode.State,
+			Player:  action.ActionNode.Player,
+			Depth:   depth,
+			Visits:  action.Visits,
+			Value:   1 - float64(action.Points)/2/float64(action.Visits),
+			Planes:  BoardPlanes(action.ActionNode.State, action.ActionNode.Player == T.PlayerA, nPositions),
+			Policy:  make([]float64, nPositions+1),
+			Actions: make([]ExportAction, 0, len(action.ActionNode.Actions)),
+		}
+
+		var sum uint64
+		for _, a := range action.ActionNode.Actions {
+			sum += a.Visits
+		}
+
+		for _, a := range action.ActionNode.Actions {
+			exportAction := ExportAction{X: a.X, Y: a.Y, Pass: a.Pass, Visits: a.Visits}
+			if a.Visits > 0 {
+				exportAction.Value = float64(a.Points) / 2 / float64(a.Visits)
+			}
+			if sum > 0 {
+				record.Policy[PolicyIndex(Action{X: a.X, Y: a.Y, Pass: a.Pass}, rows, nPositions)] += float64(a.Visits) / float64(sum)
+			}
+			record.Actions = append(record.Actions, exportAction)
+		}
+
+		return fn(record)
+	})
+}
+
+// NewExportWriter - Returns a writer for the given format, fileName is used as is for JSONL and as prefix for NPY
+func NewExportWriter(format int, fileName string, game BoardGame) (ExportWriter, error) {
+	switch format {
+	case JSONLExport:
+		return NewJSONLWriter(fileName)
+	case NpyExport:
+		return NewNpyExportWriter(fileName, boardPositions(game))
+	default:
+		fmt.Println("No export format corresponding to given format number")
+		return nil, fmt.Errorf("error, no export format corresponding to given format number")
+	}
+}
+
+// JSONLWriter - Writes exported records as one JSON object per line
+type JSONLWriter struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter - Creates a JSONL file
+func NewJSONLWriter(fileName string) (*JSONLWriter, error) {
+	f, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", fileName, err)
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+
+	return &JSONLWriter{file: f, writer: w, encoder: json.NewEncoder(w)}, nil
+}
+
+// Write - Writes one record
+func (J *JSONLWriter) Write(record ExportRecord) error {
+	if err := J.encoder.Encode(record); err != nil {
+		fmt.Printf("Error while writing JSONL record: %s\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// Close - Flushes and closes the file
+func (J *JSONLWriter) Close() error {
+	defer func(f *os.File) { _ = f.Close() }(J.file)
+
+	if err := J.writer.Flush(); err != nil {
+		fmt.Printf("Error while writing JSONL file: %s\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// NpyExportWriter - Writes exported records as three NPY files, <prefix>-planes.npy (N, 2*positions),
+// <prefix>-policy.npy (N, positions+1) and <prefix>-value.npy (N,)
+type NpyExportWriter struct {
+	planes *nn.NpyWriter
+	policy *nn.NpyWriter
+	value  *nn.NpyWriter
+}
+
+// NewNpyExportWriter - Creates the NPY files for a board with nPositions positions
+func NewNpyExportWriter(prefix string, nPositions int) (npyExportWriter *NpyExportWriter, err error) {
+	npyExportWriter = &NpyExportWriter{}
+
+	if npyExportWriter.planes, err = nn.NewNpyWriter(fmt.Sprintf("%s-planes.npy", prefix), []int{2 * nPositions}); err != nil {
+		return nil, err
+	}
+	if npyExportWriter.policy, err = nn.NewNpyWriter(fmt.Sprintf("%s-policy.npy", prefix), []int{nPositions + 1}); err != nil {
+		_ = npyExportWriter.planes.Close()
+		return nil, err
+	}
+	if npyExportWriter.value, err = nn.NewNpyWriter(fmt.Sprintf("%s-value.npy", prefix), nil); err != nil {
+		_ = npyExportWriter.planes.Close()
+		_ = npyExportWriter.policy.Close()
+		return nil, err
+	}
+
+	return
+}
+
+// Write - Writes one record
+func (N *NpyExportWriter) Write(record ExportRecord) (err error) {
+	if err = N.planes.Write(record.Planes); err != nil {
+		return
+	}
+	if err = N.policy.Write(record.Policy); err != nil {
+		return
+	}
+
+	return N.value.Write([]float64{record.Value})
+}
+
+// Close - Closes all files
+func (N *NpyExportWriter) Close() (err error) {
+	for _, w := range []*nn.NpyWriter{N.planes, N.policy, N.value} {
+		if e := w.Close(); e != nil {
+			err = e
+		}
+	}
+
+	return
+}
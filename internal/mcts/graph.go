@@ -0,0 +1,212 @@
+package mcts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"io"
+	"strings"
+)
+
+// Graph formats
+const (
+	DOTGraph int = iota
+	JSONGraph
+)
+
+// TreeGraph - The part of the node tree under a position as a graph. Nodes reached by several paths are only
+// included once, hence the graph may have several edges leading to the same node.
+type TreeGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode - A node of a tree graph, visits and value are the sums over its exposed actions and the value is for
+// the player in turn. Winner is only given for end nodes and is empty for a draw, and Hidden is the number of actions
+// not yet exposed, which have no edges.
+type GraphNode struct {
+	Id       string  `json:"id"`
+	State    string  `json:"state"`
+	Player   string  `json:"player"`
+	Position string  `json:"position"`
+	Depth    int     `json:"depth"`
+	Visits   uint64  `json:"visits"`
+	Value    float64 `json:"value"`
+	IsEnd    bool    `json:"isEnd"`
+	Winner   string  `json:"winner,omitempty"`
+	Expanded bool    `json:"expanded"`
+	Hidden   int     `json:"hidden"`
+}
+
+// GraphEdge - An action of a tree graph, the value is for the player making the move. Solved tells that the value
+// of the action is proven from the subtree under it, SolvedValue is then the proven value for the player making the
+// move.
+type GraphEdge struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Move        string  `json:"move"`
+	Visits      uint64  `json:"visits"`
+	Value       float64 `json:"value"`
+	Solved      bool    `json:"solved"`
+	SolvedValue float64 `json:"solvedValue"`
+}
+
+// BuildGraph - Returns the graph of the node tree under the position given by state and player in turn, the top
+// node if state is empty. Nodes are added breadth first down to maxDepth plies below the position following
+// actions with at least minVisits visits. Actions are solved walking at most conf.SolveNodeLimit nodes.
+func (T *Tree) BuildGraph(state, player string, maxDepth int, minVisits uint64) (graph TreeGraph, err error) {
+	var root db.MCNode
+	var rootKey []byte
+	if state == "" {
+		var top db.Action
+		if top, err = T.NodeDB.GetTopAction(); err != nil {
+			return
+		}
+		root, rootKey = top.ActionNode, top.ActionNodeKey
+	} else {
+		rootKey = db.StateKey(state, player == T.PlayerA)
+		if root, err = T.NodeDB.GetNode(rootKey); err != nil {
+			fmt.Printf("Error, position not found in the tree: %s\n", err)
+			return
+		}
+	}
+
+	solver := T.NewSolver(conf.SolveNodeLimit)
+	ids := map[string]string{string(rootKey): T.addGraphNode(&graph, root, 0)}
+	level := []db.MCNode{root}
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var next []db.MCNode
+		for _, node := range level {
+			from := ids[string(db.StateKey(node.State, node.Player == T.PlayerA))]
+
+			for _, a := range node.Actions {
+				if a.Visits < minVisits {
+					continue
+				}
+
+				var child db.MCNode
+				if child, err = T.NodeDB.GetNode(a.ActionNodeKey); err != nil {
+					return
+				}
+
+				to, seen := ids[string(a.ActionNodeKey)]
+				if !seen {
+					to = T.addGraphNode(&graph, child, depth)
+					ids[string(a.ActionNodeKey)] = to
+					next = append(next, child)
+				}
+
+				edge := GraphEdge{
+					From:   from,
+					To:     to,
+					Move:   FormatMove(Action{X: a.X, Y: a.Y, Pass: a.Pass}),
+					Visits: a.Visits,
+				}
+				if a.Visits > 0 {
+					edge.Value = float64(a.Points) / 2 / float64(a.Visits)
+				}
+				if edge.SolvedValue, edge.Solved, err = solver.SolveAction(a, node.Player); err != nil {
+					return
+				}
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+		level = next
+	}
+
+	return
+}
+
+// addGraphNode - Adds a node to the graph and returns its id
+func (T *Tree) addGraphNode(graph *TreeGraph, node db.MCNode, depth int) string {
+	graphNode := GraphNode{
+		Id:       fmt.Sprintf("n%d", len(graph.Nodes)),
+		State:    node.State,
+		Player:   node.Player,
+		Position: T.Game.FormatPosition(node.State, node.Player),
+		Depth:    depth,
+		IsEnd:    node.IsEnd,
+		Expanded: node.Actions != nil,
+		Hidden:   node.NHiddenActions,
+	}
+
+	var points uint64
+	for _, a := range node.Actions {
+		graphNode.Visits += a.Visits
+		points += a.Points
+	}
+	if graphNode.Visits > 0 {
+		graphNode.Value = float64(points) / 2 / float64(graphNode.Visits)
+	}
+
+	if node.IsEnd {
+		_, graphNode.Winner = T.Game.SetState(node.State, node.Player)
+	}
+
+	graph.Nodes = append(graph.Nodes, graphNode)
+
+	return graphNode.Id
+}
+
+// WriteGraph - Writes the graph in the given format
+func WriteGraph(w io.Writer, graph TreeGraph, format int) error {
+	switch format {
+	case DOTGraph:
+		return writeDOT(w, graph)
+	case JSONGraph:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(graph)
+	default:
+		fmt.Println("No graph format corresponding to given format number")
+		return fmt.Errorf("error, no graph format corresponding to given format number")
+	}
+}
+
+// writeDOT - Writes the graph in the Graphviz DOT language. Nodes are labelled with their boards, end nodes are
+// filled and edges of solved actions are bold and labelled with their proven values.
+func writeDOT(w io.Writer, graph TreeGraph) error {
+	b := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintln(b, "digraph tree {")
+	_, _ = fmt.Fprintln(b, "  node [shape=box, fontname=\"Courier\"];")
+	_, _ = fmt.Fprintln(b, "  edge [fontname=\"Helvetica\", fontsize=10];")
+
+	for _, n := range graph.Nodes {
+		label := strings.ReplaceAll(strings.TrimRight(RenderBoard(n.Position), "\n"), "\n", "\\l") + "\\l"
+		attributes := ""
+		switch {
+		case n.IsEnd && n.Winner == "":
+			label += "draw\\l"
+			attributes = ", style=filled, fillcolor=lightgrey"
+		case n.IsEnd:
+			label += fmt.Sprintf("%s wins\\l", n.Winner)
+			attributes = ", style=filled, fillcolor=lightgrey"
+		case n.Visits > 0:
+			label += fmt.Sprintf("%s to move, %d visits, %.3f\\l", n.Player, n.Visits, n.Value)
+		default:
+			label += fmt.Sprintf("%s to move\\l", n.Player)
+		}
+		if n.Hidden > 0 {
+			label += fmt.Sprintf("%d hidden actions\\l", n.Hidden)
+		}
+		if n.Depth == 0 {
+			attributes += ", peripheries=2"
+		}
+		_, _ = fmt.Fprintf(b, "  %s [label=\"%s\"%s];\n", n.Id, label, attributes)
+	}
+
+	for _, e := range graph.Edges {
+		attributes, solved := "", ""
+		if e.Solved {
+			attributes, solved = ", style=bold", fmt.Sprintf("\\n%s", SolvedResult(e.SolvedValue))
+		}
+		_, _ = fmt.Fprintf(b, "  %s -> %s [label=\"%s\\n%d\\n%.3f%s\"%s];\n", e.From, e.To, e.Move, e.Visits, e.Value, solved, attributes)
+	}
+
+	_, _ = fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
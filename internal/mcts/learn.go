@@ -4,12 +4,20 @@ import (
 	"fmt"
 	"github.com/gostonefire/go-mcts-v3/internal/conf"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
 	"math/rand"
 	"sort"
 )
 
+// Selection policies available in learning mode
+const (
+	UCTSelection  = conf.UCTSelection
+	PUCTSelection = conf.PUCTSelection
+)
+
 // Select - Traverses the tree to find node to explore or exploit.
-// It returns all traversed node up to and including the leaf node.
+// It returns all traversed node up to and including the leaf node, or nil if a stop criterion is met in which case
+// StoppedBy tells which.
 func (T *Tree) Select() (actions []db.Action, err error) {
 	action, err := T.NodeDB.GetTopAction()
 	if err != nil {
@@ -21,10 +29,19 @@ func (T *Tree) Select() (actions []db.Action, err error) {
 		T.printStatistics(false)
 	}
 
-	// Check if we have reached max number of finished rounds
-	if T.Rounds >= T.MaxRounds {
-		fmt.Println("\nMax rounds reached")
-		T.printStatistics(true)
+	// Keep the node hash map from filling up
+	if T.Rounds > 0 && math.Mod(T.Rounds, conf.StopCheckRounds) == 0 {
+		if err = T.resizeIfNeeded(); err != nil {
+			return
+		}
+	}
+
+	// Check if we have reached max number of finished rounds or any other stop criterion
+	if T.StoppedBy, err = T.stopCriterion(action); err != nil || T.StoppedBy != "" {
+		if T.StoppedBy != "" {
+			fmt.Printf("\nLearning stopped, %s\n", T.StoppedBy)
+			T.printStatistics(true)
+		}
 
 		return
 	}
@@ -40,8 +57,16 @@ func (T *Tree) Select() (actions []db.Action, err error) {
 		var selected int
 		var maxUCT float64
 
-		if rand.Float32() < conf.RandomRoundThreshold {
-			selected = rand.Intn(len(action.ActionNode.Actions))
+		// Progressive widening may allow the node to expose one more child given its visits
+		err = T.widen(&action, len(actions)+1)
+		if err != nil {
+			return
+		}
+
+		if T.Rand.Float32() < T.RandomRoundThreshold {
+			selected = T.Rand.Intn(len(action.ActionNode.Actions))
+		} else if T.PolicyModel != nil {
+			selected = T.selectPUCT(action, len(actions) == 1)
 		} else {
 			for i, a := range action.ActionNode.Actions {
 				// Nodes without Visits shall always be chosen ahead already visited nodes
@@ -51,7 +76,7 @@ func (T *Tree) Select() (actions []db.Action, err error) {
 				}
 
 				// Get UCT from child
-				uctValue, err = uct(action.Visits, a.Visits, a.Points)
+				uctValue, err = uct(action.Visits, a.Visits, a.Points, T.UCTConstant)
 				if err != nil {
 					fmt.Printf("Error: %s\n", err)
 					return
@@ -81,6 +106,47 @@ func (T *Tree) Select() (actions []db.Action, err error) {
 	}
 }
 
+// selectPUCT - Returns index of the child with the highest PUCT value, Dirichlet noise is added to the priors if
+// the parent is the root
+func (T *Tree) selectPUCT(parent db.Action, isRoot bool) (selected int) {
+	children := parent.ActionNode.Actions
+
+	var noise []float64
+	if isRoot && T.DirichletEpsilon > 0 {
+		noise = T.rootDirichletNoise(len(children))
+	}
+
+	maxPUCT := math.Inf(-1)
+	for i, a := range children {
+		prior := float64(a.Prior)
+		if noise != nil {
+			prior = (1-T.DirichletEpsilon)*prior + T.DirichletEpsilon*noise[i]
+		}
+
+		puctValue := puct(parent.Visits, a.Visits, a.Points, prior, T.PUCTConstant)
+		if puctValue > maxPUCT {
+			selected = i
+			maxPUCT = puctValue
+		}
+	}
+
+	return
+}
+
+// rootDirichletNoise - Returns the Dirichlet noise for the n children of the root. The noise is kept for
+// conf.DirichletNoiseRounds rounds, or until progressive widening exposes another child, and is drawn from a
+// generator seeded by the seed of the tree and the interval of rounds. It thereby neither consumes values of the
+// random generator of the tree nor changes when learning is continued in the middle of an interval.
+func (T *Tree) rootDirichletNoise(n int) []float64 {
+	interval := int64(T.Rounds) / conf.DirichletNoiseRounds
+	if len(T.rootNoise) != n || T.rootNoiseInterval != interval {
+		rng := rand.New(rand.NewSource(T.Seed ^ interval))
+		T.rootNoise, T.rootNoiseInterval = dirichlet(rng, T.DirichletAlpha, n), interval
+	}
+
+	return T.rootNoise
+}
+
 // Expand - expands one leaf with new unvisited nodes.
 // It returns one random child out of the created.
 func (T *Tree) Expand(actions []db.Action) (resultActions []db.Action, err error) {
@@ -106,26 +172,72 @@ func (T *Tree) Expand(actions []db.Action) (resultActions []db.Action, err error
 		player = T.PlayerA
 	}
 
+	// Priors from the policy model are stored with the actions and used as priorities when widening
+	var priors []float64
+	if T.PolicyModel != nil {
+		priors = T.PolicyModel.Priors(T.Game, gameActions)
+		_, _ = T.Game.SetState(action.ActionNode.State, action.ActionNode.Player)
+	}
+
+	priorities := make([]float64, nActions)
+	evaluator, canEvaluate := T.Game.(Evaluator)
+	valuer, canValue := T.PlayoutPolicy.(actionValuer)
 	for n := 0; n < nActions; n++ {
-		_, _, err = T.Game.Move(gameActions[n].X, gameActions[n].Y, gameActions[n].Pass)
+		isDone, winner, err := T.Game.Move(gameActions[n].X, gameActions[n].Y, gameActions[n].Pass)
 		if err != nil {
-			return
+			return nil, err
 		}
 		state, _ := T.Game.GetState()
+
+		// Priorities are only needed to decide which actions to expose first when widening progressively
+		if T.WideningFactor > 0 {
+			if priors != nil {
+				priorities[n] = priors[n]
+			} else if isDone {
+				priorities[n] = resultFor(action.ActionNode.Player, winner)
+			} else if canEvaluate {
+				priorities[n] = 1 - evaluator.Evaluate()
+			} else if canValue {
+				priorities[n] = valuer.Value(action.ActionNode.Player, gameActions[n])
+			} else {
+				priorities[n] = T.Rand.Float64()
+			}
+		}
 		_, _ = T.Game.SetState(action.ActionNode.State, action.ActionNode.Player)
 
 		newActions[n].X = gameActions[n].X
 		newActions[n].Y = gameActions[n].Y
 		newActions[n].Pass = gameActions[n].Pass
+		if priors != nil {
+			newActions[n].Prior = float32(priors[n])
+		}
 		states[n] = state
 	}
 
-	newActions, actionsAddress, nReused, err = T.NodeDB.AttachActionNodes(action.ActionNode.State, player, newActions, states)
+	// Order actions with highest priority first since they are exposed in order
+	if T.WideningFactor > 0 {
+		order := make([]int, nActions)
+		for n := range order {
+			order[n] = n
+		}
+		sort.SliceStable(order, func(i, j int) bool { return priorities[order[i]] > priorities[order[j]] })
+
+		orderedActions := make([]db.Action, nActions)
+		orderedStates := make([]string, nActions)
+		for n, o := range order {
+			orderedActions[n] = newActions[o]
+			orderedStates[n] = states[o]
+		}
+		newActions, states = orderedActions, orderedStates
+	}
+
+	newActions, actionsAddress, nReused, err = T.NodeDB.AttachActionNodes(action.ActionNode.State, player, newActions, states, T.widenedChildren(action.Visits))
 	if err != nil {
 		return
 	}
 	actions[lastAction].ActionNode.Actions = newActions
 	actions[lastAction].ActionNode.ActionsAddress = actionsAddress
+	actions[lastAction].ActionNode.NHiddenActions = nActions - len(newActions)
 
 	newNodes := int64(len(newActions)) - nReused
 	T.NNodes += newNodes
@@ -133,7 +245,7 @@ func (T *Tree) Expand(actions []db.Action) (resultActions []db.Action, err error
 	T.NUnexpandedNodes += newNodes - 1 // Removing one since we now have expanded one node
 
 	// Pick one random action out of the created ones
-	resultActions = append(actions, newActions[rand.Intn(nActions)])
+	resultActions = append(actions, newActions[T.Rand.Intn(len(newActions))])
 
 	// Update depth stats
 	if newNodes > 0 {
@@ -148,32 +260,91 @@ func (T *Tree) Expand(actions []db.Action) (resultActions []db.Action, err error
 	return
 }
 
-// Simulate - Plays a game to the end using simulation policy
-func (T *Tree) Simulate() (string, error) {
+// widen - Exposes one more hidden child of the node attached to the action if progressive widening allows it given
+// the visits of the action, depth is the depth of the children
+func (T *Tree) widen(action *db.Action, depth int) error {
+	node := &action.ActionNode
+	if node.NHiddenActions == 0 || len(node.Actions) >= T.widenedChildren(action.Visits) {
+		return nil
+	}
+
+	exposed, reused, err := T.NodeDB.ExposeNextAction(node.ActionsAddress)
+	if err != nil {
+		return err
+	}
+	node.Actions = append(node.Actions, exposed)
+	node.NHiddenActions--
+
+	if reused {
+		T.NReusedNodes++
+	} else {
+		T.NNodes++
+		T.NUnexpandedNodes++
+		T.DepthStats[depth]++
+	}
+
+	return nil
+}
+
+// widenedChildren - Returns the number of children a node is allowed to expose given its visits, which is
+// k * n^alpha when progressive widening is used (always at least one), otherwise all children
+func (T *Tree) widenedChildren(visits uint64) int {
+	if T.WideningFactor <= 0 {
+		return math.MaxInt
+	}
+
+	return int(math.Max(1, T.WideningFactor*math.Pow(float64(visits), T.WideningExponent)))
+}
+
+// Simulate - Plays a game to the end using simulation policy, actions are the ones traversed in the tree to get to
+// the state where the simulation starts. If a playout cutoff is set, the simulation stops after that many plies and
+// the state is evaluated instead by the leaf evaluator of the tree, or the game itself if it implements Evaluator.
+// It returns the result as seen from PlayerA (see WinnerResult), which is fractional for evaluated states.
+func (T *Tree) Simulate(actions []db.Action) (float64, error) {
+	history := T.treeHistory(actions)
+	nTree := len(history)
+	evaluator, canEvaluate := T.Game.(Evaluator)
+	if T.LeafEvaluator != nil {
+		evaluator, canEvaluate = T.LeafEvaluator, true
+	}
+
 	// Start play out simulation
-	for {
-		action := T.simulationPolicy()
+	for plies := 0; ; plies++ {
+		_, player := T.Game.GetState()
+
+		if canEvaluate && T.PlayoutCutoff > 0 && plies >= T.PlayoutCutoff {
+			T.playout = history[nTree:]
+			return T.resultForPlayer(player, evaluator.Evaluate()), nil
+		}
+
+		action := T.simulationPolicy(player, history)
+		history = append(history, PlayedAction{Player: player, Action: action})
 
 		isDone, winner, err := T.Game.Move(action.X, action.Y, action.Pass)
 		if err != nil {
 			fmt.Printf("Error while making a move: %s\n", err)
-			return "", err
+			return 0, err
 		}
 		if isDone {
-			return winner, nil
+			T.playout = history[nTree:]
+			return T.WinnerResult(winner), nil
 		}
 	}
 }
 
-// BackPropagation - Updates the tree with statistics after a simulation
-func (T *Tree) BackPropagation(actions []db.Action, winner string) error {
+// BackPropagation - Updates the tree with statistics after a simulation, result is as seen from PlayerA
+func (T *Tree) BackPropagation(actions []db.Action, result float64) error {
 	for i := len(actions) - 1; i >= 0; i-- {
-		err := T.updateActionStatistics(actions[i], winner)
+		err := T.updateActionStatistics(actions[i], result)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Let the playout policy learn from the whole game, i.e. both tree actions and actions made in the simulation
+	T.PlayoutPolicy.Update(append(T.treeHistory(actions), T.playout...), T.resultWinner(result))
+	T.playout = nil
+
 	T.Rounds++
 
 	return nil
@@ -200,16 +371,40 @@ func (T *Tree) SetNodeIsEnd(action db.Action) (err error) {
 }
 
 // simulationPolicy - Gets next Action in a simulation and applies whatever policy determined suitable
-func (T *Tree) simulationPolicy() Action {
+func (T *Tree) simulationPolicy(player string, history []PlayedAction) Action {
 	actions := T.availableGameActions()
 
-	return actions[rand.Intn(len(actions))]
+	return T.PlayoutPolicy.SelectAction(player, history, actions)
+}
+
+// treeHistory - Converts actions traversed in the tree to played actions. The first action is the top action which
+// isn't a real move and is therefore left out. Since the attached action node keeps track of the player in turn,
+// the player that made the action is the opponent of that player.
+func (T *Tree) treeHistory(actions []db.Action) []PlayedAction {
+	if len(actions) <= 1 {
+		return nil
+	}
+
+	history := make([]PlayedAction, 0, len(actions)-1)
+	for _, a := range actions[1:] {
+		history = append(history, PlayedAction{
+			Player: T.opponent(a.ActionNode.Player),
+			Action: Action{X: a.X, Y: a.Y, Pass: a.Pass},
+		})
+	}
+
+	return history
 }
 
 // availableGameActions - Returns available actions from the game in mcts Action format
 func (T *Tree) availableGameActions() []Action {
+	return gameActions(T.Game)
+}
+
+// gameActions - Returns available actions from the given game in mcts Action format, nil if the game is over
+func gameActions(game BoardGame) []Action {
 	// Get available actions from game
-	availableActions, pass := T.Game.AvailableActions()
+	availableActions, pass := game.AvailableActions()
 
 	// Handle situation where the game responds with nil instead of empty slice
 	nAvailableActions := 0
@@ -246,15 +441,10 @@ func (T *Tree) availableGameActions() []Action {
 }
 
 // updateActionStatistics - Wrapper function over the NodeDB function with similar name, but this one adds the
-// points and visits logic
-func (T *Tree) updateActionStatistics(action db.Action, winner string) (err error) {
-	newPoints := action.Points
-	if winner == "" {
-		// It's a draw
-		newPoints += 1
-	} else if action.ActionNode.Player == winner {
-		newPoints += 2
-	}
+// points and visits logic. Points are given to the player that made the action, which is the opponent of the
+// player in turn in the attached action node.
+func (T *Tree) updateActionStatistics(action db.Action, result float64) (err error) {
+	newPoints := action.Points + resultPoints(T.Rand, T.resultForPlayer(T.opponent(action.ActionNode.Player), result))
 
 	newVisits := action.Visits + 1
 
@@ -271,12 +461,24 @@ func (T *Tree) updateActionStatistics(action db.Action, winner string) (err erro
 		if action.ActionNode.Player == T.PlayerB {
 			player = 1
 		}
-		T.AI.RecordStateStatistics(player, action.ActionNode.State, newVisits, float64(newPoints)/2)
+		err = T.AI.RecordStateStatistics(player, action.ActionNode.State, newVisits, float64(newPoints)/2)
 	}
 
 	return
 }
 
+// resultPoints - Converts a result (1 for a win, 0.5 for a draw and 0 for a loss) to points. Points are integers
+// where a win gives 2 and a draw 1, so fractional results from evaluated simulations are rounded up or down at
+// random in proportion to the fraction. That keeps the expected points equal to the result.
+func resultPoints(rng *rand.Rand, result float64) uint64 {
+	points := math.Floor(2 * result)
+	if rng.Float64() < 2*result-points {
+		points++
+	}
+
+	return uint64(points)
+}
+
 func (T *Tree) printStatistics(finalPrint bool) {
 	fmt.Printf(
 		"%.0f rounds, %d unique nodes, %d reused nodes, %d unexpanded nodes\n",
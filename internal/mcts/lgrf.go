@@ -0,0 +1,145 @@
+package mcts
+
+import "sort"
+
+// lgrKey1 - Key identifying a reply by a player to the last action made
+type lgrKey1 struct {
+	player string
+	last   Action
+}
+
+// lgrKey2 - Key identifying a reply by a player to the last two actions made
+type lgrKey2 struct {
+	player     string
+	secondLast Action
+	last       Action
+}
+
+// LGRF2 - Last-Good-Reply with Forgetting playout policy, remembering replies to both the last action and the last
+// two actions. A reply is stored when the player replying went on to win and is forgotten again when a game where
+// it was played was lost. Whenever no stored reply is available (or it isn't a legal action) the fallback policy
+// is used.
+type LGRF2 struct {
+	fallback PlayoutPolicy
+	replies1 map[lgrKey1]Action
+	replies2 map[lgrKey2]Action
+}
+
+// NewLGRF2 - Returns a new LGRF-2 playout policy using the given policy as fallback
+func NewLGRF2(fallback PlayoutPolicy) *LGRF2 {
+	return &LGRF2{
+		fallback: fallback,
+		replies1: make(map[lgrKey1]Action),
+		replies2: make(map[lgrKey2]Action),
+	}
+}
+
+// SelectAction - Returns the last good reply if any is stored and legal, otherwise asks the fallback policy
+func (L *LGRF2) SelectAction(player string, history []PlayedAction, actions []Action) Action {
+	n := len(history)
+	if n >= 2 {
+		reply, ok := L.replies2[lgrKey2{player: player, secondLast: history[n-2].Action, last: history[n-1].Action}]
+		if ok && containsAction(actions, reply) {
+			return reply
+		}
+	}
+	if n >= 1 {
+		reply, ok := L.replies1[lgrKey1{player: player, last: history[n-1].Action}]
+		if ok && containsAction(actions, reply) {
+			return reply
+		}
+	}
+
+	return L.fallback.SelectAction(player, history, actions)
+}
+
+// Update - Stores replies made by the winner and forgets replies made by the loser, a draw leaves replies as is
+func (L *LGRF2) Update(history []PlayedAction, winner string) {
+	if winner != "" {
+		for i := 1; i < len(history); i++ {
+			h := history[i]
+			key1 := lgrKey1{player: h.Player, last: history[i-1].Action}
+
+			if h.Player == winner {
+				L.replies1[key1] = h.Action
+				if i >= 2 {
+					L.replies2[lgrKey2{player: h.Player, secondLast: history[i-2].Action, last: history[i-1].Action}] = h.Action
+				}
+				continue
+			}
+
+			if reply, ok := L.replies1[key1]; ok && reply == h.Action {
+				delete(L.replies1, key1)
+			}
+			if i >= 2 {
+				key2 := lgrKey2{player: h.Player, secondLast: history[i-2].Action, last: history[i-1].Action}
+				if reply, ok := L.replies2[key2]; ok && reply == h.Action {
+					delete(L.replies2, key2)
+				}
+			}
+		}
+	}
+
+	L.fallback.Update(history, winner)
+}
+
+// saveStatistics - Adds the stored replies, ordered by player and the actions replied to, to the playout statistics
+// together with what the fallback policy has learned
+func (L *LGRF2) saveStatistics(statistics *PlayoutStatistics) {
+	statistics.Replies = make([]LastGoodReply, 0, len(L.replies1)+len(L.replies2))
+	for key, reply := range L.replies1 {
+		statistics.Replies = append(statistics.Replies, LastGoodReply{Player: key.player, Last: key.last, Reply: reply})
+	}
+	for key, reply := range L.replies2 {
+		secondLast := key.secondLast
+		statistics.Replies = append(statistics.Replies, LastGoodReply{Player: key.player, SecondLast: &secondLast, Last: key.last, Reply: reply})
+	}
+
+	sort.Slice(statistics.Replies, func(i, j int) bool {
+		a, b := statistics.Replies[i], statistics.Replies[j]
+		if a.Player != b.Player {
+			return a.Player < b.Player
+		}
+		if (a.SecondLast == nil) != (b.SecondLast == nil) {
+			return a.SecondLast == nil
+		}
+		if a.SecondLast != nil && *a.SecondLast != *b.SecondLast {
+			return actionLess(*a.SecondLast, *b.SecondLast)
+		}
+
+		return actionLess(a.Last, b.Last)
+	})
+
+	if keeper, ok := L.fallback.(statisticsKeeper); ok {
+		keeper.saveStatistics(statistics)
+	}
+}
+
+// restoreStatistics - Replaces the stored replies with the ones in the playout statistics and lets the fallback
+// policy restore what it has learned
+func (L *LGRF2) restoreStatistics(statistics PlayoutStatistics) {
+	L.replies1 = make(map[lgrKey1]Action)
+	L.replies2 = make(map[lgrKey2]Action)
+	for _, r := range statistics.Replies {
+		if r.SecondLast == nil {
+			L.replies1[lgrKey1{player: r.Player, last: r.Last}] = r.Reply
+		} else {
+			L.replies2[lgrKey2{player: r.Player, secondLast: *r.SecondLast, last: r.Last}] = r.Reply
+		}
+	}
+
+	if keeper, ok := L.fallback.(statisticsKeeper); ok {
+		keeper.restoreStatistics(statistics)
+	}
+}
+
+// containsAction - Returns whether the action is among the given actions
+func containsAction(actions []Action, action Action) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package mcts
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// mastUnseenValue - Value given to actions that hasn't been seen yet, being optimistic makes sure they get tried
+const mastUnseenValue float64 = 1
+
+// mastKey - Key identifying an action made by a specific player in the MAST statistics
+type mastKey struct {
+	player string
+	action Action
+}
+
+// mastStat - Accumulated results for one action
+type mastStat struct {
+	visits float64
+	points float64
+}
+
+// MAST - Move-Average Sampling Technique playout policy.
+// It keeps global (i.e. regardless of state) averages of results for each action and player, and samples actions
+// in playouts using a Gibbs (softmax) distribution over those averages.
+type MAST struct {
+	temperature float64
+	stats       map[mastKey]*mastStat
+	rng         *rand.Rand
+}
+
+// NewMAST - Returns a new MAST playout policy drawing random values from the given generator, a lower temperature
+// gives a greedier policy
+func NewMAST(temperature float64, rng *rand.Rand) *MAST {
+	return &MAST{
+		temperature: temperature,
+		stats:       make(map[mastKey]*mastStat),
+		rng:         rng,
+	}
+}
+
+// SelectAction - Samples an action out of the given using Gibbs sampling over the MAST values
+func (M *MAST) SelectAction(player string, _ []PlayedAction, actions []Action) Action {
+	if len(actions) == 1 {
+		return actions[0]
+	}
+
+	var sum float64
+	weights := make([]float64, len(actions))
+	for i, a := range actions {
+		weights[i] = math.Exp(M.Value(player, a) / M.temperature)
+		sum += weights[i]
+	}
+
+	r := M.rng.Float64() * sum
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return actions[i]
+		}
+	}
+
+	return actions[len(actions)-1]
+}
+
+// Update - Adds the result of a game to the statistics of every action played in it
+func (M *MAST) Update(history []PlayedAction, winner string) {
+	for _, h := range history {
+		key := mastKey{player: h.Player, action: h.Action}
+		stat, ok := M.stats[key]
+		if !ok {
+			stat = &mastStat{}
+			M.stats[key] = stat
+		}
+		stat.visits++
+		stat.points += resultFor(h.Player, winner)
+	}
+}
+
+// Value - Returns the average result for the given player making the given action
+func (M *MAST) Value(player string, action Action) float64 {
+	stat, ok := M.stats[mastKey{player: player, action: action}]
+	if !ok || stat.visits == 0 {
+		return mastUnseenValue
+	}
+
+	return stat.points / stat.visits
+}
+
+// saveStatistics - Adds the MAST statistics, ordered by player and action, to the playout statistics
+func (M *MAST) saveStatistics(statistics *PlayoutStatistics) {
+	statistics.MAST = make([]MASTStatistic, 0, len(M.stats))
+	for key, stat := range M.stats {
+		statistics.MAST = append(statistics.MAST, MASTStatistic{Player: key.player, Action: key.action, Visits: stat.visits, Points: stat.points})
+	}
+
+	sort.Slice(statistics.MAST, func(i, j int) bool {
+		a, b := statistics.MAST[i], statistics.MAST[j]
+		if a.Player != b.Player {
+			return a.Player < b.Player
+		}
+
+		return actionLess(a.Action, b.Action)
+	})
+}
+
+// restoreStatistics - Replaces the MAST statistics with the ones in the playout statistics
+func (M *MAST) restoreStatistics(statistics PlayoutStatistics) {
+	M.stats = make(map[mastKey]*mastStat, len(statistics.MAST))
+	for _, s := range statistics.MAST {
+		M.stats[mastKey{player: s.Player, action: s.Action}] = &mastStat{visits: s.Visits, points: s.Points}
+	}
+}
@@ -0,0 +1,38 @@
+package mcts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatMove - Formats an action in algebraic notation, i.e. column letter followed by row number (e.g. C4),
+// or pass
+func FormatMove(action Action) string {
+	if action.Pass {
+		return "pass"
+	}
+
+	return fmt.Sprintf("%c%d", 'A'+action.X, action.Y+1)
+}
+
+// ParseMove - Parses a move in algebraic notation (case insensitive) or pass
+func ParseMove(move string) (action Action, err error) {
+	move = strings.ToUpper(strings.TrimSpace(move))
+	if move == "PASS" {
+		return Action{Pass: true}, nil
+	}
+
+	if len(move) < 2 || move[0] < 'A' || move[0] > 'Z' {
+		fmt.Printf("Error, malformed move: %s\n", move)
+		return action, fmt.Errorf("error, malformed move: %s", move)
+	}
+
+	row, err := strconv.Atoi(move[1:])
+	if err != nil || row < 1 || row > 256 {
+		fmt.Printf("Error, malformed move: %s\n", move)
+		return action, fmt.Errorf("error, malformed move: %s", move)
+	}
+
+	return Action{X: move[0] - 'A', Y: uint8(row - 1)}, nil
+}
@@ -0,0 +1,157 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/nn"
+	"math/rand"
+)
+
+// NewGameNetwork - Returns a new network shaped for the board of the game, i.e. two input planes (see BoardPlanes)
+// and one policy output per position plus one for pass, with weights randomly initialized from rng
+func NewGameNetwork(game BoardGame, hiddenSizes []int, rng *rand.Rand) *nn.Network {
+	nPositions := boardPositions(game)
+
+	return nn.NewNetwork(2*nPositions, hiddenSizes, nPositions+1, rng)
+}
+
+// CheckGameNetwork - Returns an error if the network isn't shaped for the board of the game
+func CheckGameNetwork(game BoardGame, network *nn.Network) error {
+	nPositions := boardPositions(game)
+	if network.NInput != 2*nPositions || network.NPolicy != nPositions+1 {
+		fmt.Println("Error, network is not shaped for the board of the game")
+		return fmt.Errorf("error, network is not shaped for the board of the game")
+	}
+
+	return nil
+}
+
+// BoardPlanes - Encodes a state as input planes seen from the player in turn. The first plane holds the markers of
+// the player in turn and the second the markers of the opponent, each with one entry per position in the same order
+// as in the state string. States shorter than nPositions are left padded with empty positions.
+func BoardPlanes(state string, playerInTurnIsA bool, nPositions int) []float64 {
+	planes := make([]float64, 2*nPositions)
+	offset := nPositions - len(state)
+
+	own, other := byte('1'), byte('2')
+	if !playerInTurnIsA {
+		own, other = other, own
+	}
+
+	for i := 0; i < len(state); i++ {
+		switch state[i] {
+		case own:
+			planes[offset+i] = 1
+		case other:
+			planes[nPositions+offset+i] = 1
+		}
+	}
+
+	return planes
+}
+
+// PolicyIndex - Returns the index of an action in a policy vector. Positions are indexed as in the state string,
+// i.e. X * rows + Y, and a pass comes last.
+func PolicyIndex(action Action, rows uint8, nPositions int) int {
+	if action.Pass {
+		return nPositions
+	}
+
+	return int(action.X)*int(rows) + int(action.Y)
+}
+
+// boardPositions - Returns number of positions on the board of the game
+func boardPositions(game BoardGame) int {
+	columns, rows := game.BoardSize()
+
+	return int(columns) * int(rows)
+}
+
+// NetworkEvaluator - Evaluator using the value head of a network on the current state of a game
+type NetworkEvaluator struct {
+	network *nn.Network
+	game    BoardGame
+}
+
+// NewNetworkEvaluator - Returns a new network based evaluator for the game
+func NewNetworkEvaluator(network *nn.Network, game BoardGame) *NetworkEvaluator {
+	return &NetworkEvaluator{network: network, game: game}
+}
+
+// Evaluate - Returns the estimated probability that the player in turn wins
+func (N *NetworkEvaluator) Evaluate() float64 {
+	state, player := N.game.GetState()
+	value, _ := N.network.Predict(BoardPlanes(state, player == N.game.GetPlayers()[0], boardPositions(N.game)))
+
+	return value
+}
+
+// NetworkPolicy - Policy model using the policy head of a network
+type NetworkPolicy struct {
+	network *nn.Network
+}
+
+// NewNetworkPolicy - Returns a new network based policy model
+func NewNetworkPolicy(network *nn.Network) *NetworkPolicy {
+	return &NetworkPolicy{network: network}
+}
+
+// Priors - Returns the network policy restricted to the given actions
+func (N *NetworkPolicy) Priors(game BoardGame, actions []Action) []float64 {
+	state, player := game.GetState()
+	nPositions := boardPositions(game)
+	_, rows := game.BoardSize()
+	_, policy := N.network.Predict(BoardPlanes(state, player == game.GetPlayers()[0], nPositions))
+
+	var sum float64
+	priors := make([]float64, len(actions))
+	for i, a := range actions {
+		priors[i] = policy[PolicyIndex(a, rows, nPositions)]
+		sum += priors[i]
+	}
+	if sum == 0 {
+		return NewUniformPolicy().Priors(game, actions)
+	}
+	for i := range priors {
+		priors[i] /= sum
+	}
+
+	return priors
+}
+
+// NetworkSamples - Collects training samples from every node in the tree reached through an action with at least
+// minVisits visits. The value target is the mean value for the player in turn and the policy target is the visit
+// distribution over the children (no policy target for nodes without visited children).
+func (T *Tree) NetworkSamples(minVisits uint64) (samples []nn.Sample, err error) {
+	nPositions := boardPositions(T.Game)
+	_, rows := T.Game.BoardSize()
+
+	err = WalkTree(T.NodeDB, minVisits, 0, func(action db.Action, _ int) error {
+		if action.Visits == 0 {
+			return nil
+		}
+
+		// Points on the action are given to the player that made it, i.e. the opponent of the player in turn
+		sample := nn.Sample{
+			Input: BoardPlanes(action.ActionNode.State, action.ActionNode.Player == T.PlayerA, nPositions),
+			Value: 1 - float64(action.Points)/2/float64(action.Visits),
+		}
+
+		var sum uint64
+		for _, a := range action.ActionNode.Actions {
+			sum += a.Visits
+		}
+		if sum > 0 {
+			sample.Policy = make([]float64, nPositions+1)
+			for _, a := range action.ActionNode.Actions {
+				sample.Policy[PolicyIndex(Action{X: a.X, Y: a.Y, Pass: a.Pass}, rows, nPositions)] += float64(a.Visits) / float64(sum)
+			}
+		}
+
+		samples = append(samples, sample)
+
+		return nil
+	})
+
+	return
+}
@@ -0,0 +1,138 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"math/rand"
+)
+
+// OnlinePlayer - Player searching the current state of the game with a fresh in memory UCT search of a given
+// number of rounds (the budget) and random playouts. It neither uses nor changes any stored tree.
+type OnlinePlayer struct {
+	budget int
+}
+
+// onlineNode - Node in the in memory tree of an online search. Points are given to the player that made the action
+// leading to the node.
+type onlineNode struct {
+	action   Action
+	player   string
+	visits   uint64
+	points   uint64
+	isEnd    bool
+	winner   string
+	children []*onlineNode
+	untried  []Action
+}
+
+// NewOnlinePlayer - Returns a new online MCTS player searching budget rounds per move
+func NewOnlinePlayer(budget int) *OnlinePlayer {
+	return &OnlinePlayer{budget: budget}
+}
+
+// Name - Returns name of the player
+func (O *OnlinePlayer) Name() string {
+	return fmt.Sprintf("mcts:%d", O.budget)
+}
+
+// SelectAction - Searches the current state of the game and returns the most visited action
+func (O *OnlinePlayer) SelectAction(game BoardGame) (Action, error) {
+	state, player := game.GetState()
+	root := &onlineNode{untried: gameActions(game)}
+	if root.untried == nil {
+		return Action{}, fmt.Errorf("no available actions, game is already over")
+	}
+
+	for i := 0; i < O.budget; i++ {
+		err := O.searchRound(game, root)
+		_, _ = game.SetState(state, player)
+		if err != nil {
+			return Action{}, err
+		}
+	}
+
+	var best *onlineNode
+	for _, c := range root.children {
+		if best == nil || c.visits > best.visits {
+			best = c
+		}
+	}
+	if best == nil {
+		return root.untried[rand.Intn(len(root.untried))], nil
+	}
+
+	return best.action, nil
+}
+
+// searchRound - One round of select, expand, simulate and back propagation starting with the game in the state of
+// the root
+func (O *OnlinePlayer) searchRound(game BoardGame, root *onlineNode) (err error) {
+	node := root
+	path := []*onlineNode{root}
+
+	// Select down to a node with untried actions or to the end of the game
+	for !node.isEnd && len(node.untried) == 0 {
+		var selected *onlineNode
+		var maxUCT float64
+		for _, c := range node.children {
+			if c.visits == 0 {
+				selected = c
+				break
+			}
+
+			var uctValue float64
+			if uctValue, err = uct(node.visits, c.visits, c.points, conf.UCTConstant); err != nil {
+				return
+			}
+			if selected == nil || uctValue > maxUCT {
+				selected, maxUCT = c, uctValue
+			}
+		}
+
+		if _, _, err = game.Move(selected.action.X, selected.action.Y, selected.action.Pass); err != nil {
+			return
+		}
+		node = selected
+		path = append(path, node)
+	}
+
+	// Expand one untried action
+	winner := node.winner
+	if !node.isEnd {
+		i := rand.Intn(len(node.untried))
+		action := node.untried[i]
+		node.untried[i] = node.untried[len(node.untried)-1]
+		node.untried = node.untried[:len(node.untried)-1]
+
+		_, mover := game.GetState()
+		child := &onlineNode{action: action, player: mover}
+		if child.isEnd, child.winner, err = game.Move(action.X, action.Y, action.Pass); err != nil {
+			return
+		}
+		if !child.isEnd {
+			child.untried = gameActions(game)
+		}
+		node.children = append(node.children, child)
+		node = child
+		path = append(path, node)
+
+		// Simulate to the end of the game using random actions
+		isDone := child.isEnd
+		winner = child.winner
+		for !isDone {
+			actions := gameActions(game)
+			a := actions[rand.Intn(len(actions))]
+			if isDone, winner, err = game.Move(a.X, a.Y, a.Pass); err != nil {
+				return
+			}
+		}
+	}
+
+	// Back propagate, a win gives 2 points and a draw 1
+	for _, n := range path {
+		n.visits++
+		n.points += uint64(2 * resultFor(n.player, winner))
+	}
+
+	return
+}
@@ -2,8 +2,10 @@ package mcts
 
 import (
 	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
-	"math/rand"
+	"strings"
+	"time"
 )
 
 // MoveResult - A structure holding information about the result after a play move
@@ -13,6 +15,7 @@ type MoveResult struct {
 	OpponentMoveX    uint8
 	OpponentMoveY    uint8
 	OpponentMovePass bool
+	OpponentStats    string // Source of the opponent move with statistics, e.g. "tree visits=1520 value=0.612"
 }
 
 // ResetPlayPlayerA - Resets the tree for use in a new play against the tree as an opponent where the human act as
@@ -25,6 +28,7 @@ func (T *Tree) ResetPlayPlayerA() error {
 		return err
 	}
 	T.AtNode = action.ActionNode
+	T.Record = T.NewGameRecord("", T.PlayerA, T.PlayerB, time.Now().Format("2006.01.02"))
 
 	return nil
 }
@@ -39,12 +43,35 @@ func (T *Tree) ResetPlayPlayerB() error {
 		return err
 	}
 	T.AtNode = action.ActionNode
+	T.Record = T.NewGameRecord("", T.PlayerA, T.PlayerA, time.Now().Format("2006.01.02"))
 
 	_, _ = T.opponentMove()
 
 	return nil
 }
 
+// ResetPlayPosition - Resets the tree for use in a new play against the tree as an opponent starting from the given
+// position where the human act as the given player. The tree makes the first move unless the human is in turn.
+func (T *Tree) ResetPlayPosition(state, playerInTurn, human string) (err error) {
+	if isDone, _ := T.Game.SetState(state, playerInTurn); isDone {
+		return fmt.Errorf("game is already over in given position")
+	}
+	T.Record = T.NewGameRecord(T.Game.FormatPosition(state, playerInTurn), playerInTurn, T.opponent(human), time.Now().Format("2006.01.02"))
+
+	T.AtNode, err = T.NodeDB.GetNode(db.StateKey(state, playerInTurn == T.PlayerA))
+	if err != nil {
+		// Position is not in the explored part of the game tree
+		T.AtNode = db.MCNode{}
+		err = nil
+	}
+
+	if playerInTurn != human {
+		_, err = T.opponentMove()
+	}
+
+	return
+}
+
 // PlayExploitPlayer - Plays a move as Player and the model uses strict tree evaluation,
 // i.e. it never explores it only exploits on statistics learned during learn phase.
 // If game tree is not fully explored the opponent will play either by policy if such exist or
@@ -71,20 +98,13 @@ func (T *Tree) PlayExploitPlayer(x uint8, y uint8, pass bool) (MoveResult, error
 	}
 
 	// Check if we are still in an explored part of the game tree and update state (AtNode) accordingly
-	if T.AtNode.Assigned && T.AtNode.Actions != nil {
-		for _, a := range T.AtNode.Actions {
-			if a.X == x && a.Y == y && a.Pass == pass {
-				T.AtNode, err = T.NodeDB.GetNode(a.ActionNodeKey)
-				if err != nil {
-					return MoveResult{}, err
-				}
-				break
-			}
-		}
+	if err = T.followAction(Action{X: x, Y: y, Pass: pass}); err != nil {
+		return MoveResult{}, err
 	}
 
 	// Make move in game
 	isDone, winner, _ := T.Game.Move(x, y, pass)
+	T.recordMove(Action{X: x, Y: y, Pass: pass}, "", isDone, winner)
 
 	// Did the move result in end of game
 	if isDone {
@@ -101,9 +121,16 @@ func (T *Tree) PlayExploitPlayer(x uint8, y uint8, pass bool) (MoveResult, error
 }
 
 func (T *Tree) opponentMove() (MoveResult, error) {
-	// Find best move for opponent
-	var action Action
-	if T.AtNode.Actions != nil {
+	// Find best move for opponent, the opening book has precedence over the tree
+	var stats string
+	action, move, inBook := T.bookAction(T.Game)
+	if inBook {
+		stats = fmt.Sprintf("book visits=%d value=%.3f", move.Visits, move.Value)
+		if err := T.followAction(action); err != nil {
+			return MoveResult{}, err
+		}
+
+	} else if T.AtNode.Actions != nil {
 		var selected int
 		var maxScore float64
 
@@ -124,6 +151,7 @@ func (T *Tree) opponentMove() (MoveResult, error) {
 			Y:    T.AtNode.Actions[selected].Y,
 			Pass: T.AtNode.Actions[selected].Pass,
 		}
+		stats = fmt.Sprintf("tree visits=%d value=%.3f", T.AtNode.Actions[selected].Visits, maxScore)
 
 		var err error
 		T.AtNode, err = T.NodeDB.GetNode(T.AtNode.Actions[selected].ActionNodeKey)
@@ -138,10 +166,11 @@ func (T *Tree) opponentMove() (MoveResult, error) {
 		}
 
 		T.AtNode = db.MCNode{}
-		action = actions[rand.Intn(len(actions))]
+		action, stats = T.aiMove(T.Game, actions, T.Rand.Intn)
 	}
 
 	isDone, winner, _ := T.Game.Move(action.X, action.Y, action.Pass)
+	T.recordMove(action, stats, isDone, winner)
 
 	return MoveResult{
 		IsDone:           isDone,
@@ -149,10 +178,88 @@ func (T *Tree) opponentMove() (MoveResult, error) {
 		OpponentMoveX:    action.X,
 		OpponentMoveY:    action.Y,
 		OpponentMovePass: action.Pass,
+		OpponentStats:    stats,
 	}, nil
 
 }
 
+// recordMove - Adds a move to the game record if there is one, and sets its result if the game is over
+func (T *Tree) recordMove(action Action, comment string, isDone bool, winner string) {
+	if T.Record == nil {
+		return
+	}
+
+	T.Record.AddMove(action, comment)
+	if isDone {
+		T.Record.SetWinner(winner)
+	}
+}
+
+// followAction - Moves AtNode to the node the action leads to if still in an explored part of the game tree,
+// otherwise AtNode is left out of the tree
+func (T *Tree) followAction(action Action) (err error) {
+	if !T.AtNode.Assigned || T.AtNode.Actions == nil {
+		T.AtNode = db.MCNode{}
+		return
+	}
+
+	for _, a := range T.AtNode.Actions {
+		if a.X == action.X && a.Y == action.Y && a.Pass == action.Pass {
+			T.AtNode, err = T.NodeDB.GetNode(a.ActionNodeKey)
+			return
+		}
+	}
+	T.AtNode = db.MCNode{}
+
+	return
+}
+
+// aiMove - Picks a move when out of the explored tree using the AI DB as fallback policy. The move leading to the
+// state with the highest value for the player in turn is picked if any is labelled high value, otherwise a random
+// move avoiding states labelled low value, and if all moves are labelled low value then a random move. The given
+// game is moved around while evaluating but restored before returning. Random moves are picked by intn, which must
+// be safe for the caller's use, e.g. rand.Intn when called from concurrent games. It also returns the source of the
+// move with statistics.
+func (T *Tree) aiMove(game BoardGame, actions []Action, intn func(n int) int) (Action, string) {
+	state, player := game.GetState()
+
+	// The AI DB keeps track of the player that made the move to get to a state, 1 for the first player
+	var aiPlayer int
+	if player == T.PlayerA {
+		aiPlayer = 1
+	}
+
+	var best Action
+	var bestValue float64
+	var foundBest bool
+	notBad := make([]Action, 0, len(actions))
+	for _, a := range actions {
+		value, found := 0.5, false
+		if _, _, err := game.Move(a.X, a.Y, a.Pass); err == nil {
+			resultState, _ := game.GetState()
+			value, found = T.AI.StateValue(aiPlayer, strings.TrimLeft(resultState, "0"))
+		}
+		_, _ = game.SetState(state, player)
+
+		if !found {
+			notBad = append(notBad, a)
+		} else if float32(value) >= conf.AIHighValueThreshold && value > bestValue {
+			best, bestValue, foundBest = a, value, true
+		} else if float32(value) > conf.AILowValueThreshold {
+			notBad = append(notBad, a)
+		}
+	}
+
+	if foundBest {
+		return best, fmt.Sprintf("ai value=%.3f", bestValue)
+	}
+	if len(notBad) > 0 {
+		return notBad[intn(len(notBad))], "random"
+	}
+
+	return actions[intn(len(actions))], "random"
+}
+
 func (T *Tree) PrintBoard() {
 	T.Game.PrintBoard()
 }
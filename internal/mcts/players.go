@@ -0,0 +1,150 @@
+package mcts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gostonefire/filehashmap/crt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math/rand"
+)
+
+// GamePlayer - Interface for players choosing actions in the current state of a game, e.g. in tournaments.
+// A player may be shared between concurrently played games so all game state must be taken from the given game.
+// A player may move the game around while choosing but restores its state before returning.
+type GamePlayer interface {
+	Name() string
+	SelectAction(game BoardGame) (Action, error) // Returns: Action to play and error
+}
+
+// ExploitPlayer - Player strictly exploiting the statistics of a stored tree as in play mode, i.e. opening book
+// first, then the action with the highest value in the tree and the AI DB when out of the explored tree
+type ExploitPlayer struct {
+	tree *Tree
+}
+
+// NewExploitPlayer - Returns a new player exploiting the given tree
+func NewExploitPlayer(tree *Tree) *ExploitPlayer {
+	return &ExploitPlayer{tree: tree}
+}
+
+// Name - Returns name of the player
+func (E *ExploitPlayer) Name() string {
+	return fmt.Sprintf("exploit:%s", E.tree.Run)
+}
+
+// SelectAction - Returns the action to play in the current state of the game. Nodes are looked up by state rather
+// than followed from the top, so the player has no state of its own.
+func (E *ExploitPlayer) SelectAction(game BoardGame) (Action, error) {
+	actions := gameActions(game)
+	if actions == nil {
+		return Action{}, fmt.Errorf("no available actions, game is already over")
+	}
+
+	if action, _, inBook := E.tree.bookAction(game); inBook {
+		return action, nil
+	}
+
+	state, player := game.GetState()
+	node, err := E.tree.NodeDB.GetNode(db.StateKey(state, player == E.tree.PlayerA))
+	if err != nil && !errors.Is(err, crt.NoRecordFound{}) {
+		return Action{}, err
+	}
+
+	var best *db.Action
+	var maxScore float64
+	for i, a := range node.Actions {
+		if a.Visits == 0 {
+			continue
+		}
+
+		score := float64(a.Points) / 2 / float64(a.Visits)
+		if best == nil || score > maxScore {
+			best = &node.Actions[i]
+			maxScore = score
+		}
+	}
+	if best != nil {
+		return Action{X: best.X, Y: best.Y, Pass: best.Pass}, nil
+	}
+
+	// Games may be played concurrently and the tree may be learning, so random moves are not drawn from the tree
+	action, _ := E.tree.aiMove(game, actions, rand.Intn)
+
+	return action, nil
+}
+
+// RandomPlayer - Player picking actions uniformly at random
+type RandomPlayer struct{}
+
+// NewRandomPlayer - Returns a new random player
+func NewRandomPlayer() *RandomPlayer {
+	return &RandomPlayer{}
+}
+
+// Name - Returns name of the player
+func (R *RandomPlayer) Name() string {
+	return "random"
+}
+
+// SelectAction - Returns a random action out of the available in the current state of the game
+func (R *RandomPlayer) SelectAction(game BoardGame) (Action, error) {
+	actions := gameActions(game)
+	if actions == nil {
+		return Action{}, fmt.Errorf("no available actions, game is already over")
+	}
+
+	return actions[rand.Intn(len(actions))], nil
+}
+
+// HeuristicPlayer - Player picking the action with the best static evaluation (see Evaluator) of the resulting
+// state, an immediate win is always taken. Games not implementing Evaluator only gets immediate wins and otherwise
+// random actions.
+type HeuristicPlayer struct{}
+
+// NewHeuristicPlayer - Returns a new heuristic player
+func NewHeuristicPlayer() *HeuristicPlayer {
+	return &HeuristicPlayer{}
+}
+
+// Name - Returns name of the player
+func (H *HeuristicPlayer) Name() string {
+	return "heuristic"
+}
+
+// SelectAction - Returns the action giving the resulting state with the highest value for the player in turn,
+// ties are broken at random
+func (H *HeuristicPlayer) SelectAction(game BoardGame) (Action, error) {
+	actions := gameActions(game)
+	if actions == nil {
+		return Action{}, fmt.Errorf("no available actions, game is already over")
+	}
+
+	evaluator, canEvaluate := game.(Evaluator)
+	state, player := game.GetState()
+
+	var best []Action
+	var bestValue float64
+	for _, a := range actions {
+		value := 0.5
+		isDone, winner, err := game.Move(a.X, a.Y, a.Pass)
+		if err != nil {
+			_, _ = game.SetState(state, player)
+			return Action{}, err
+		}
+		if isDone {
+			value = resultFor(player, winner)
+		} else if canEvaluate {
+			// The evaluation is for the player in turn after the action, i.e. the opponent
+			value = 1 - evaluator.Evaluate()
+		}
+		_, _ = game.SetState(state, player)
+
+		if best == nil || value > bestValue {
+			best, bestValue = []Action{a}, value
+		} else if value == bestValue {
+			best = append(best, a)
+		}
+	}
+
+	return best[rand.Intn(len(best))], nil
+}
@@ -0,0 +1,119 @@
+package mcts
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Playout policies available for selection in learning mode
+const (
+	RandomPlayout int = iota
+	MASTPlayout
+	LGRF2Playout
+)
+
+// PlayoutPolicy - Interface for policies that picks actions during the simulation (playout) phase.
+// A policy only deals with Action (X, Y, Pass) and player names, hence it works with any BoardGame.
+type PlayoutPolicy interface {
+	SelectAction(player string, history []PlayedAction, actions []Action) Action // Returns one of the given actions
+	Update(history []PlayedAction, winner string)                                // Winner (empty string is a draw)
+}
+
+// actionValuer - Interface for playout policies that can tell the value of an action for a player
+type actionValuer interface {
+	Value(player string, action Action) float64
+}
+
+// statisticsKeeper - Interface for playout policies that learn from results, what they have learned is saved with
+// the learner state so that continued learning plays out as if it never was interrupted
+type statisticsKeeper interface {
+	saveStatistics(statistics *PlayoutStatistics)
+	restoreStatistics(statistics PlayoutStatistics)
+}
+
+// PlayoutStatistics - What adaptive playout policies have learned, in a form that can be saved with the learner
+// state
+type PlayoutStatistics struct {
+	MAST    []MASTStatistic `json:"mast,omitempty"`
+	Replies []LastGoodReply `json:"replies,omitempty"`
+}
+
+// MASTStatistic - Accumulated results of one action made by one player in MAST
+type MASTStatistic struct {
+	Player string  `json:"player"`
+	Action Action  `json:"action"`
+	Visits float64 `json:"visits"`
+	Points float64 `json:"points"`
+}
+
+// LastGoodReply - A reply stored by LGRF-2, to the last action only when there is no second last action
+type LastGoodReply struct {
+	Player     string  `json:"player"`
+	SecondLast *Action `json:"secondLast,omitempty"`
+	Last       Action  `json:"last"`
+	Reply      Action  `json:"reply"`
+}
+
+// PlayedAction - An action together with the player that made it
+type PlayedAction struct {
+	Player string
+	Action Action
+}
+
+// NewPlayoutPolicy - Returns the playout policy corresponding to the given policy number drawing random values
+// from the given generator
+func NewPlayoutPolicy(policy int, temperature float64, rng *rand.Rand) (PlayoutPolicy, error) {
+	switch policy {
+	case RandomPlayout:
+		return NewRandomPolicy(rng), nil
+	case MASTPlayout:
+		return NewMAST(temperature, rng), nil
+	case LGRF2Playout:
+		return NewLGRF2(NewMAST(temperature, rng)), nil
+	default:
+		fmt.Println("No playout policy corresponding to given policy number")
+		return nil, fmt.Errorf("error, no playout policy corresponding to given policy number")
+	}
+}
+
+// RandomPolicy - Playout policy that picks actions uniformly at random
+type RandomPolicy struct {
+	rng *rand.Rand
+}
+
+// NewRandomPolicy - Returns a new random playout policy drawing random values from the given generator
+func NewRandomPolicy(rng *rand.Rand) *RandomPolicy {
+	return &RandomPolicy{rng: rng}
+}
+
+// SelectAction - Returns a random action out of the given
+func (R *RandomPolicy) SelectAction(_ string, _ []PlayedAction, actions []Action) Action {
+	return actions[R.rng.Intn(len(actions))]
+}
+
+// Update - Random policy doesn't learn anything from results
+func (R *RandomPolicy) Update(_ []PlayedAction, _ string) {}
+
+// actionLess - Orders actions by X, Y and pass, which gives saved statistics the same order every time
+func actionLess(a, b Action) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+
+	return !a.Pass && b.Pass
+}
+
+// resultFor - Returns the result of a game from the perspective of the given player, 1 for a win, 0.5 for a draw
+// and 0 for a loss
+func resultFor(player, winner string) float64 {
+	if winner == "" {
+		return 0.5
+	} else if winner == player {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,39 @@
+package mcts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePosition - Parses a position given either in the FEN like notation of the game or as a base 3 state
+// optionally followed by the player in turn (the first player if left out). An empty position gives the start
+// position of the game.
+func (T *Tree) ParsePosition(position string) (state, playerInTurn string, err error) {
+	position = strings.TrimSpace(position)
+	if position == "" {
+		T.Game.Reset()
+		state, playerInTurn = T.Game.GetState()
+		return
+	}
+
+	if strings.Contains(position, "/") {
+		return T.Game.ParsePosition(position)
+	}
+
+	fields := strings.Fields(position)
+	if len(fields) > 2 || strings.Trim(fields[0], "012") != "" {
+		fmt.Printf("Error, malformed position: %s\n", position)
+		return "", "", fmt.Errorf("error, malformed position: %s", position)
+	}
+
+	state, playerInTurn = fields[0], T.PlayerA
+	if len(fields) == 2 {
+		playerInTurn = fields[1]
+		if playerInTurn != T.PlayerA && playerInTurn != T.PlayerB {
+			fmt.Printf("Error, unknown player in turn: %s\n", playerInTurn)
+			return "", "", fmt.Errorf("error, unknown player in turn: %s", playerInTurn)
+		}
+	}
+
+	return
+}
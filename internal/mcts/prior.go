@@ -0,0 +1,193 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/nn"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Prior models available for selection in learning mode
+const (
+	UniformPrior int = iota
+	HeuristicPrior
+	AIPrior
+	NetworkPrior
+)
+
+// PolicyModel - Interface for models giving prior probabilities P(s,a) for actions in the current state of a game.
+// A model may move the game around while evaluating but the caller is responsible for restoring the state.
+type PolicyModel interface {
+	Priors(game BoardGame, actions []Action) []float64 // Returns: One prior per action, summing up to 1
+}
+
+// StateValuer - Interface for anything that knows the value of a state for a player, e.g. the AI DB.
+// Player is 1 for the first player to play and 0 for the second.
+type StateValuer interface {
+	StateValue(player int, state string) (value float64, found bool)
+}
+
+// NewPolicyModel - Returns the policy model corresponding to the given model number
+func NewPolicyModel(model int, stateValuer StateValuer, network *nn.Network, temperature float64) (PolicyModel, error) {
+	switch model {
+	case UniformPrior:
+		return NewUniformPolicy(), nil
+	case HeuristicPrior:
+		return NewHeuristicPolicy(temperature), nil
+	case AIPrior:
+		return NewAIPolicy(stateValuer, temperature), nil
+	case NetworkPrior:
+		return NewNetworkPolicy(network), nil
+	default:
+		fmt.Println("No prior model corresponding to given model number")
+		return nil, fmt.Errorf("error, no prior model corresponding to given model number")
+	}
+}
+
+// UniformPolicy - Policy model giving every action the same prior
+type UniformPolicy struct{}
+
+// NewUniformPolicy - Returns a new uniform policy model
+func NewUniformPolicy() *UniformPolicy {
+	return &UniformPolicy{}
+}
+
+// Priors - Returns the same prior for all actions
+func (U *UniformPolicy) Priors(_ BoardGame, actions []Action) []float64 {
+	priors := make([]float64, len(actions))
+	for i := range priors {
+		priors[i] = 1 / float64(len(actions))
+	}
+
+	return priors
+}
+
+// HeuristicPolicy - Policy model using the static evaluation of the game (see Evaluator) of the state resulting
+// from each action. Games not implementing Evaluator gets uniform priors.
+type HeuristicPolicy struct {
+	temperature float64
+}
+
+// NewHeuristicPolicy - Returns a new heuristic policy model, a lower temperature gives sharper priors
+func NewHeuristicPolicy(temperature float64) *HeuristicPolicy {
+	return &HeuristicPolicy{temperature: temperature}
+}
+
+// Priors - Returns a softmax over the evaluated value of each resulting state as seen by the player in turn
+func (H *HeuristicPolicy) Priors(game BoardGame, actions []Action) []float64 {
+	evaluator, canEvaluate := game.(Evaluator)
+	if !canEvaluate {
+		return NewUniformPolicy().Priors(game, actions)
+	}
+
+	state, player := game.GetState()
+	values := make([]float64, len(actions))
+	for i, a := range actions {
+		isDone, winner, err := game.Move(a.X, a.Y, a.Pass)
+		if err != nil {
+			values[i] = 0
+		} else if isDone {
+			values[i] = resultFor(player, winner)
+		} else {
+			values[i] = 1 - evaluator.Evaluate()
+		}
+		_, _ = game.SetState(state, player)
+	}
+
+	return softmax(values, H.temperature)
+}
+
+// AIPolicy - Policy model using values of resulting states as recorded in the AI DB. States not present in the
+// AI DB are given a neutral value.
+type AIPolicy struct {
+	stateValuer StateValuer
+	temperature float64
+}
+
+// NewAIPolicy - Returns a new AI DB backed policy model, a lower temperature gives sharper priors
+func NewAIPolicy(stateValuer StateValuer, temperature float64) *AIPolicy {
+	return &AIPolicy{stateValuer: stateValuer, temperature: temperature}
+}
+
+// Priors - Returns a softmax over the AI DB values of each resulting state for the player in turn
+func (A *AIPolicy) Priors(game BoardGame, actions []Action) []float64 {
+	state, player := game.GetState()
+
+	// The AI DB keeps track of the player that made the move to get to a state, 1 for the first player
+	var aiPlayer int
+	if player == game.GetPlayers()[0] {
+		aiPlayer = 1
+	}
+
+	values := make([]float64, len(actions))
+	for i, a := range actions {
+		values[i] = 0.5
+		if _, _, err := game.Move(a.X, a.Y, a.Pass); err == nil {
+			resultState, _ := game.GetState()
+			if value, found := A.stateValuer.StateValue(aiPlayer, strings.TrimLeft(resultState, "0")); found {
+				values[i] = value
+			}
+		}
+		_, _ = game.SetState(state, player)
+	}
+
+	return softmax(values, A.temperature)
+}
+
+// softmax - Returns the softmax of the values given a temperature
+func softmax(values []float64, temperature float64) []float64 {
+	maxValue := math.Inf(-1)
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+
+	var sum float64
+	result := make([]float64, len(values))
+	for i, v := range values {
+		result[i] = math.Exp((v - maxValue) / temperature)
+		sum += result[i]
+	}
+	for i := range result {
+		result[i] /= sum
+	}
+
+	return result
+}
+
+// dirichlet - Returns a sample from a symmetric Dirichlet distribution with n components
+func dirichlet(rng *rand.Rand, alpha float64, n int) []float64 {
+	var sum float64
+	sample := make([]float64, n)
+	for i := range sample {
+		sample[i] = gamma(rng, alpha)
+		sum += sample[i]
+	}
+	for i := range sample {
+		sample[i] /= sum
+	}
+
+	return sample
+}
+
+// gamma - Returns a sample from a gamma distribution with scale 1 using the Marsaglia and Tsang method
+func gamma(rng *rand.Rand, alpha float64) float64 {
+	if alpha < 1 {
+		return gamma(rng, alpha+1) * math.Pow(rng.Float64(), 1/alpha)
+	}
+
+	d := alpha - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mcts
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// xoshiroSource - Random source using the xoshiro256** generator. Its whole state is four integers that can be
+// saved and set again, which makes it possible to continue a sequence of random values from where it was left
+// without drawing the values already drawn once more.
+type xoshiroSource struct {
+	s [4]uint64
+}
+
+// newXoshiroSource - Returns a new source seeded with the given seed
+func newXoshiroSource(seed int64) *xoshiroSource {
+	source := &xoshiroSource{}
+	source.Seed(seed)
+
+	return source
+}
+
+// Int63 - Returns a non-negative pseudo-random 63-bit integer
+func (X *xoshiroSource) Int63() int64 {
+	return int64(X.Uint64() >> 1)
+}
+
+// Uint64 - Returns a pseudo-random 64-bit integer
+func (X *xoshiroSource) Uint64() uint64 {
+	result := bits.RotateLeft64(X.s[1]*5, 7) * 9
+
+	t := X.s[1] << 17
+	X.s[2] ^= X.s[0]
+	X.s[3] ^= X.s[1]
+	X.s[1] ^= X.s[2]
+	X.s[0] ^= X.s[3]
+	X.s[2] ^= t
+	X.s[3] = bits.RotateLeft64(X.s[3], 45)
+
+	return result
+}
+
+// Seed - Seeds the source, the seed is expanded to the state by splitmix64 which never gives an all zero state
+func (X *xoshiroSource) Seed(seed int64) {
+	z := uint64(seed)
+	for i := range X.s {
+		z += 0x9e3779b97f4a7c15
+		r := z
+		r = (r ^ (r >> 30)) * 0xbf58476d1ce4e5b9
+		r = (r ^ (r >> 27)) * 0x94d049bb133111eb
+		X.s[i] = r ^ (r >> 31)
+	}
+}
+
+// state - Returns the state of the source
+func (X *xoshiroSource) state() []uint64 {
+	return append([]uint64{}, X.s[:]...)
+}
+
+// setState - Sets the state of the source to one returned by state
+func (X *xoshiroSource) setState(state []uint64) error {
+	if len(state) != len(X.s) || state[0]|state[1]|state[2]|state[3] == 0 {
+		fmt.Printf("Error, invalid random source state %v\n", state)
+		return fmt.Errorf("error, invalid random source state %v", state)
+	}
+	copy(X.s[:], state)
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package mcts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*
+	Game record format, loosely modelled on PGN:
+
+	[Game "nodetree6x6-1"]
+	[Date "2026.10.18"]
+	[PlayerA "B"]
+	[PlayerB "W"]
+	[Engine "W"]
+	[Position "....../....../..WB../..BW../....../...... B"]
+	[Result "1-0"]
+
+	1. C4 {tree visits=1520 value=0.612} D3 2. pass E3 {book visits=310 value=0.550} 1-0
+
+	Tags come first, one per line. Engine is the player the tree played and is left out for games not played against
+	the tree. Position is the start position in the notation of the game and is left out for the standard start
+	position. Moves are in algebraic notation (column letter and row number) or pass, with a move number before each
+	move of PlayerA ("1..." if the record starts with PlayerB in turn). A comment in braces after a move holds engine
+	statistics or annotations. Result is "1-0" (PlayerA wins), "0-1" (PlayerB wins), "1/2-1/2" (draw) or "*"
+	(unfinished) and ends the move text.
+*/
+
+// Results of a game record
+const (
+	ResultPlayerA    string = "1-0"
+	ResultPlayerB    string = "0-1"
+	ResultDraw       string = "1/2-1/2"
+	ResultUnfinished string = "*"
+)
+
+// GameRecord - A recorded game. Engine is the player the tree played, empty if not played against the tree.
+// FirstPlayer is the player in turn in the start position, it isn't written as a tag since it is given by Position,
+// and it defaults to PlayerA when empty.
+type GameRecord struct {
+	Game        string
+	Date        string
+	PlayerA     string
+	PlayerB     string
+	Engine      string
+	Position    string
+	Result      string
+	FirstPlayer string
+	Moves       []RecordedMove
+}
+
+// RecordedMove - A move in a game record with an optional comment
+type RecordedMove struct {
+	Action  Action
+	Comment string
+}
+
+// NewGameRecord - Returns a new unfinished game record for the tree starting from the given position (empty string
+// for the start position of the game) with the given player in turn, where the tree plays the engine player (empty
+// string if the tree doesn't play)
+func (T *Tree) NewGameRecord(position, firstPlayer, engine, date string) *GameRecord {
+	return &GameRecord{
+		Game:        T.Name,
+		Date:        date,
+		PlayerA:     T.PlayerA,
+		PlayerB:     T.PlayerB,
+		Engine:      engine,
+		Position:    position,
+		Result:      ResultUnfinished,
+		FirstPlayer: firstPlayer,
+	}
+}
+
+// AddMove - Adds a move to the record
+func (G *GameRecord) AddMove(action Action, comment string) {
+	G.Moves = append(G.Moves, RecordedMove{Action: action, Comment: comment})
+}
+
+// SetWinner - Sets the result of the record given the winner, empty string is a draw
+func (G *GameRecord) SetWinner(winner string) {
+	switch winner {
+	case G.PlayerA:
+		G.Result = ResultPlayerA
+	case G.PlayerB:
+		G.Result = ResultPlayerB
+	default:
+		G.Result = ResultDraw
+	}
+}
+
+// Write - Writes the record
+func (G *GameRecord) Write(w io.Writer) (err error) {
+	bw := bufio.NewWriter(w)
+
+	tags := [][2]string{{"Game", G.Game}, {"Date", G.Date}, {"PlayerA", G.PlayerA}, {"PlayerB", G.PlayerB}}
+	if G.Engine != "" {
+		tags = append(tags, [2]string{"Engine", G.Engine})
+	}
+	if G.Position != "" {
+		tags = append(tags, [2]string{"Position", G.Position})
+	}
+	tags = append(tags, [2]string{"Result", G.Result})
+	for _, t := range tags {
+		_, _ = fmt.Fprintf(bw, "[%s \"%s\"]\n", t[0], t[1])
+	}
+	_, _ = bw.WriteString("\n")
+
+	tokens := make([]string, 0, 2*len(G.Moves)+1)
+	player := G.FirstPlayer
+	if player == "" {
+		player = G.PlayerA
+	}
+	number := 1
+	for i, m := range G.Moves {
+		if player == G.PlayerA {
+			tokens = append(tokens, fmt.Sprintf("%d.", number))
+		} else if i == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d...", number))
+		}
+		tokens = append(tokens, FormatMove(m.Action))
+		if m.Comment != "" {
+			tokens = append(tokens, fmt.Sprintf("{%s}", m.Comment))
+		}
+
+		if player == G.PlayerB {
+			number++
+			player = G.PlayerA
+		} else {
+			player = G.PlayerB
+		}
+	}
+	tokens = append(tokens, G.Result)
+
+	// Wrap move text at about 80 characters
+	var lineLength int
+	for _, t := range tokens {
+		if lineLength > 0 && lineLength+len(t) >= 80 {
+			_, _ = bw.WriteString("\n")
+			lineLength = 0
+		} else if lineLength > 0 {
+			_, _ = bw.WriteString(" ")
+			lineLength++
+		}
+		_, _ = bw.WriteString(t)
+		lineLength += len(t)
+	}
+	_, _ = bw.WriteString("\n")
+
+	if err = bw.Flush(); err != nil {
+		fmt.Printf("Error while writing game record: %s\n", err)
+	}
+
+	return
+}
+
+// Save - Saves the record to file
+func (G *GameRecord) Save(fileName string) (err error) {
+	f, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	return G.Write(f)
+}
+
+// LoadGameRecord - Loads a game record from file
+func LoadGameRecord(fileName string) (record *GameRecord, err error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		fmt.Printf("Error while opening %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	return ParseGameRecord(f)
+}
+
+// ParseGameRecord - Parses a game record. A plain move list without tags is accepted as well, e.g. "C4 D3 pass".
+func ParseGameRecord(r io.Reader) (record *GameRecord, err error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		fmt.Printf("Error while reading game record: %s\n", err)
+		return
+	}
+
+	record = &GameRecord{Result: ResultUnfinished}
+	var moveText strings.Builder
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name, value, found := strings.Cut(strings.Trim(line, "[]"), " ")
+			if !found {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), "\"")
+			switch name {
+			case "Game":
+				record.Game = value
+			case "Date":
+				record.Date = value
+			case "PlayerA":
+				record.PlayerA = value
+			case "PlayerB":
+				record.PlayerB = value
+			case "Engine":
+				record.Engine = value
+			case "Position":
+				record.Position = value
+			case "Result":
+				record.Result = value
+			}
+			continue
+		}
+		moveText.WriteString(line)
+		moveText.WriteString(" ")
+	}
+
+	text := moveText.String()
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t")
+		if text == "" {
+			break
+		}
+
+		// Comments belong to the preceding move
+		if text[0] == '{' {
+			end := strings.IndexByte(text, '}')
+			if end < 0 {
+				fmt.Println("Error, unterminated comment in game record")
+				return nil, fmt.Errorf("error, unterminated comment in game record")
+			}
+			if len(record.Moves) > 0 {
+				record.Moves[len(record.Moves)-1].Comment = strings.TrimSpace(text[1:end])
+			}
+			text = text[end+1:]
+			continue
+		}
+
+		token := text
+		if end := strings.IndexAny(text, " \t{"); end >= 0 {
+			token = text[:end]
+		}
+		text = text[len(token):]
+
+		switch {
+		case strings.HasSuffix(token, "."):
+			// Move number
+		case token == ResultPlayerA || token == ResultPlayerB || token == ResultDraw || token == ResultUnfinished:
+			record.Result = token
+		default:
+			var action Action
+			if action, err = ParseMove(token); err != nil {
+				return nil, err
+			}
+			record.AddMove(action, "")
+		}
+	}
+
+	return
+}
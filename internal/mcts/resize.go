@@ -0,0 +1,50 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+)
+
+// resizeIfNeeded - Rehashes the node hash map into a larger map if its load factor or share of overflowed records
+// has grown too high, which happens when unique states were underestimated
+func (T *Tree) resizeIfNeeded() (err error) {
+	nodeTree, ok := T.NodeDB.(*db.NodeTree)
+	if !ok {
+		return
+	}
+
+	usage, err := nodeTree.HashMapUsage()
+	if err != nil {
+		return
+	}
+	if usage.LoadFactor(T.NNodes) < conf.ResizeLoadFactor && usage.OverflowShare(T.NNodes) < conf.ResizeOverflowShare {
+		return
+	}
+
+	uniqueStates := ResizedUniqueStates(usage, T.NNodes)
+	fmt.Printf(
+		"Resizing node hash map, load factor %.2f and %.1f%% overflowed, from %d to %d buckets\n",
+		usage.LoadFactor(T.NNodes),
+		100*usage.OverflowShare(T.NNodes),
+		usage.Buckets,
+		uniqueStates,
+	)
+	if err = nodeTree.Resize(uniqueStates); err != nil {
+		fmt.Println("Error while resizing node hash map")
+		return
+	}
+
+	return
+}
+
+// ResizedUniqueStates - Returns the unique states to give a resized node hash map, ResizeGrowthFactor times the
+// current buckets but at least one bucket per record
+func ResizedUniqueStates(usage db.HashMapUsage, records int64) int64 {
+	uniqueStates := usage.Buckets * conf.ResizeGrowthFactor
+	if uniqueStates < records {
+		uniqueStates = records
+	}
+
+	return uniqueStates
+}
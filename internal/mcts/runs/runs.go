@@ -0,0 +1,389 @@
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// manifestFile - Name of the manifest file in the folder of a run
+const manifestFile = "manifest.json"
+
+// treeFilePattern - Pattern of node tree file names, the tree name (see conf.TreeName) with board size and game
+// number followed by a dash or a dot
+var treeFilePattern = regexp.MustCompile(`^(nodetree(\d+)x\d+-(\d+))[-.]`)
+
+// Manifest - Description of a run, i.e. a folder in the data directory holding a node tree with all its files
+type Manifest struct {
+	Run     string    `json:"run"`
+	GameId  int       `json:"gameId"`
+	Size    uint8     `json:"size"`
+	Tree    string    `json:"tree"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// RunInfo - A run as listed, with the rounds learned if known and the total size of its files
+type RunInfo struct {
+	Manifest
+	Rounds float64
+	Bytes  int64
+}
+
+// Prepare - Creates the folder and manifest of a run to learn the node tree of the given game and board size in,
+// or updates the manifest of an existing run after checking that the run is of the same game and size
+func Prepare(run string, gameId int, size uint8) (err error) {
+	manifest, err := ReadManifest(run)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err = os.MkdirAll(conf.RunDir(run), 0755); err != nil {
+			fmt.Printf("Error while creating folder of run %s, %s\n", run, err)
+			return
+		}
+		manifest = Manifest{Run: run, GameId: gameId, Size: size, Tree: conf.TreeName(gameId, size), Created: time.Now()}
+	case err != nil:
+		return
+	case manifest.GameId != gameId || manifest.Size != size:
+		fmt.Printf("Error, run %s is of game %d size %d\n", run, manifest.GameId, manifest.Size)
+		return fmt.Errorf("error, run %s is of game %d size %d", run, manifest.GameId, manifest.Size)
+	}
+
+	manifest.Updated = time.Now()
+
+	return WriteManifest(manifest)
+}
+
+// RunOf - Returns the run of the named node tree, i.e. the name of the tree files prefixed by the folder of a run
+func RunOf(name string) string {
+	return filepath.Base(filepath.Dir(name))
+}
+
+// CheckTree - Checks that the named node tree belongs to an existing run
+func CheckTree(name string) (err error) {
+	run := RunOf(name)
+	manifest, err := ReadManifest(run)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Error, no run named %s in %s\n", run, conf.DataDirectory())
+		return fmt.Errorf("error, no run named %s in %s", run, conf.DataDirectory())
+	} else if err != nil {
+		return
+	}
+
+	if manifest.Tree != filepath.Base(name) {
+		fmt.Printf("Error, run %s holds %s and not %s\n", run, manifest.Tree, filepath.Base(name))
+		return fmt.Errorf("error, run %s holds %s and not %s", run, manifest.Tree, filepath.Base(name))
+	}
+
+	return
+}
+
+// ReadManifest - Reads the manifest of the named run, the error wraps os.ErrNotExist if there is no such run
+func ReadManifest(run string) (manifest Manifest, err error) {
+	buf, err := os.ReadFile(filepath.Join(conf.RunDir(run), manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		fmt.Printf("Error while reading manifest of run %s, %s\n", run, err)
+		return
+	}
+
+	if err = json.Unmarshal(buf, &manifest); err != nil {
+		fmt.Printf("Error while decoding manifest of run %s, %s\n", run, err)
+	}
+
+	return
+}
+
+// WriteManifest - Writes the manifest of a run to its folder
+func WriteManifest(manifest Manifest) (err error) {
+	return writeManifest(conf.RunDir(manifest.Run), manifest)
+}
+
+// writeManifest - Writes the manifest of a run to the given folder
+func writeManifest(dir string, manifest Manifest) (err error) {
+	buf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		fmt.Printf("Error while encoding manifest: %s\n", err)
+		return
+	}
+
+	fileName := filepath.Join(dir, manifestFile)
+	if err = os.WriteFile(fileName, append(buf, '\n'), 0644); err != nil {
+		fmt.Printf("Error while writing %s, %s\n", fileName, err)
+	}
+
+	return
+}
+
+// List - Returns all runs in the data directory sorted by name, folders without a manifest are not runs
+func List() (runs []RunInfo, err error) {
+	dirEntries, err := os.ReadDir(conf.DataDirectory())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		fmt.Printf("Error listing directory content, %s\n", err)
+		return
+	}
+
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			continue
+		}
+
+		var manifest Manifest
+		if manifest, err = ReadManifest(e.Name()); errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return
+		}
+
+		info := RunInfo{Manifest: manifest}
+		if info.Bytes, err = folderSize(conf.RunDir(e.Name())); err != nil {
+			return
+		}
+		info.Rounds = learnedRounds(filepath.Join(conf.RunDir(e.Name()), manifest.Tree+".state"))
+		runs = append(runs, info)
+	}
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Run < runs[j].Run })
+
+	return
+}
+
+// Delete - Removes the named run with all its files
+func Delete(run string) (err error) {
+	if _, err = ReadManifest(run); errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Error, no run named %s\n", run)
+		return fmt.Errorf("error, no run named %s", run)
+	} else if err != nil {
+		return
+	}
+
+	if err = os.RemoveAll(conf.RunDir(run)); err != nil {
+		fmt.Printf("Error while removing run %s, %s\n", run, err)
+	}
+
+	return
+}
+
+// Copy - Copies the named run with all its files to a new run, e.g. to continue learning with other options
+// without touching the original. Files are copied to a temporary folder that is renamed to the folder of the new
+// run once complete, so an interrupted copy never leaves a partial run.
+func Copy(from, to string) (err error) {
+	manifest, err := ReadManifest(from)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Error, no run named %s\n", from)
+		return fmt.Errorf("error, no run named %s", from)
+	} else if err != nil {
+		return
+	}
+
+	tmpDir, err := prepareRun(to)
+	if err != nil {
+		return
+	}
+
+	dirEntries, err := os.ReadDir(conf.RunDir(from))
+	if err != nil {
+		fmt.Printf("Error listing directory content, %s\n", err)
+		_ = os.RemoveAll(tmpDir)
+		return
+	}
+	for _, e := range dirEntries {
+		if e.Type().IsRegular() && e.Name() != manifestFile {
+			if err = copyFile(filepath.Join(conf.RunDir(from), e.Name()), filepath.Join(tmpDir, e.Name())); err != nil {
+				_ = os.RemoveAll(tmpDir)
+				return
+			}
+		}
+	}
+
+	manifest.Run = to
+	manifest.Created = time.Now()
+	manifest.Updated = manifest.Created
+
+	if err = completeRun(tmpDir, manifest); err != nil {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	return
+}
+
+// FoundTree - A node tree found outside the data directory, e.g. one learned before runs were introduced
+type FoundTree struct {
+	Tree   string
+	GameId int
+	Size   uint8
+	Files  []string
+}
+
+// FindTrees - Returns the node trees in a folder, i.e. the files of node trees named as by conf.TreeName grouped by
+// tree, sorted by tree name
+func FindTrees(dir string) (trees []FoundTree, err error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Printf("Error listing directory content, %s\n", err)
+		return
+	}
+
+	found := make(map[string]*FoundTree)
+	for _, e := range dirEntries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		name := e.Name()
+		match := treeFilePattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+
+		tree, ok := found[match[1]]
+		if !ok {
+			size, _ := strconv.Atoi(match[2])
+			gameId, _ := strconv.Atoi(match[3])
+			tree = &FoundTree{Tree: match[1], GameId: gameId, Size: uint8(size)}
+			found[match[1]] = tree
+		}
+		tree.Files = append(tree.Files, filepath.Join(dir, name))
+	}
+
+	for _, tree := range found {
+		trees = append(trees, *tree)
+	}
+	sort.Slice(trees, func(i, j int) bool { return trees[i].Tree < trees[j].Tree })
+
+	return
+}
+
+// Adopt - Moves the files of a node tree found outside the data directory into a new run. The files are moved to a
+// temporary folder that is renamed to the folder of the run once complete, and moved back if moving fails.
+func Adopt(tree FoundTree, run string) (err error) {
+	tmpDir, err := prepareRun(run)
+	if err != nil {
+		return
+	}
+
+	moveBack := func(files []string) {
+		for _, file := range files {
+			_ = os.Rename(filepath.Join(tmpDir, filepath.Base(file)), file)
+		}
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	for i, file := range tree.Files {
+		if err = os.Rename(file, filepath.Join(tmpDir, filepath.Base(file))); err != nil {
+			fmt.Printf("Error while moving %s, %s\n", file, err)
+			moveBack(tree.Files[:i])
+			return
+		}
+	}
+
+	now := time.Now()
+	if err = completeRun(tmpDir, Manifest{Run: run, GameId: tree.GameId, Size: tree.Size, Tree: tree.Tree, Created: now, Updated: now}); err != nil {
+		moveBack(tree.Files)
+	}
+
+	return
+}
+
+// prepareRun - Checks that there is no run with the given name and returns a new empty temporary folder to build
+// the run in, the caller removes it if the run isn't completed
+func prepareRun(run string) (tmpDir string, err error) {
+	if _, err = os.Stat(conf.RunDir(run)); err == nil {
+		fmt.Printf("Error, run %s already exists\n", run)
+		return "", fmt.Errorf("error, run %s already exists", run)
+	}
+
+	// A leading dot keeps the temporary folder apart from runs since run names can't start with a dot
+	tmpDir = filepath.Join(conf.DataDirectory(), fmt.Sprintf(".%s.tmp", run))
+	if err = os.RemoveAll(tmpDir); err != nil {
+		fmt.Printf("Error while removing %s, %s\n", tmpDir, err)
+		return
+	}
+	if err = os.MkdirAll(tmpDir, 0755); err != nil {
+		fmt.Printf("Error while creating folder %s, %s\n", tmpDir, err)
+	}
+
+	return
+}
+
+// completeRun - Writes the manifest to the temporary folder of a run and renames the folder to the folder of the
+// run
+func completeRun(tmpDir string, manifest Manifest) (err error) {
+	if err = writeManifest(tmpDir, manifest); err != nil {
+		return
+	}
+
+	if err = os.Rename(tmpDir, conf.RunDir(manifest.Run)); err != nil {
+		fmt.Printf("Error while creating run %s, %s\n", manifest.Run, err)
+	}
+
+	return
+}
+
+// copyFile - Copies a file
+func copyFile(from, to string) (err error) {
+	src, err := os.Open(from)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", from, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(src)
+
+	dst, err := os.Create(to)
+	if err != nil {
+		fmt.Printf("Error while creating %s, %s\n", to, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(dst)
+
+	if _, err = io.Copy(dst, src); err != nil {
+		fmt.Printf("Error while copying %s to %s, %s\n", from, to, err)
+	}
+
+	return
+}
+
+// folderSize - Returns the total size of the files in a folder
+func folderSize(dir string) (size int64, err error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Printf("Error listing directory content, %s\n", err)
+		return
+	}
+
+	for _, e := range dirEntries {
+		var fileInfo os.FileInfo
+		if fileInfo, err = e.Info(); err != nil {
+			fmt.Printf("Error while stat %s, %s\n", e.Name(), err)
+			return
+		}
+		size += fileInfo.Size()
+	}
+
+	return
+}
+
+// learnedRounds - Returns the rounds learned according to a state file, zero if unknown
+func learnedRounds(stateFile string) float64 {
+	var state struct {
+		Rounds float64 `json:"rounds"`
+	}
+
+	buf, err := os.ReadFile(stateFile)
+	if err != nil {
+		return 0
+	}
+	_ = json.Unmarshal(buf, &state)
+
+	return state.Rounds
+}
@@ -0,0 +1,106 @@
+package mcts
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
+)
+
+// solvedValue - Proven value of a node for its player in turn, only valid if solved
+type solvedValue struct {
+	value  float64
+	player string
+	solved bool
+}
+
+// Solver - Proves values of actions from the subtrees under them. Results are memoized, so one solver is used for
+// the actions of a node, and at most limit nodes are walked (no limit if zero) after which nodes count as unsolved.
+type Solver struct {
+	tree   *Tree
+	solved map[string]solvedValue
+	limit  int
+}
+
+// NewSolver - Returns a new solver walking at most limit nodes, no limit if zero
+func (T *Tree) NewSolver(limit int) *Solver {
+	return &Solver{tree: T, solved: make(map[string]solvedValue), limit: limit}
+}
+
+// SolveAction - Returns the proven value of an action for the given player making it, if solved
+func (S *Solver) SolveAction(action db.Action, player string) (value float64, isSolved bool, err error) {
+	if value, isSolved, err = S.tree.solveNode(action.ActionNodeKey, S.solved, S.limit); err != nil || !isSolved {
+		return
+	}
+
+	// Values are for the player in turn of the child
+	if S.solved[string(action.ActionNodeKey)].player != player {
+		value = 1 - value
+	}
+
+	return
+}
+
+// SolvedResult - Returns the result a proven value stands for, win, loss or draw
+func SolvedResult(value float64) string {
+	switch value {
+	case 1:
+		return "win"
+	case 0:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// solveNode - Returns the proven value for the player in turn of the node with the given key, if solved. A node is
+// solved if it is an end node, if one of its actions leads to a solved win for the player in turn, or if all its
+// actions are exposed and lead to solved nodes. Results are memoized in solved, and once it holds limit nodes
+// (no limit if zero) further nodes are taken as unsolved without being walked.
+func (T *Tree) solveNode(nodeKey []byte, solved map[string]solvedValue, limit int) (value float64, isSolved bool, err error) {
+	if s, seen := solved[string(nodeKey)]; seen {
+		return s.value, s.solved, nil
+	}
+	if limit > 0 && len(solved) >= limit {
+		return
+	}
+	// Marked as unsolved while in progress in case a position repeats
+	solved[string(nodeKey)] = solvedValue{}
+
+	node, err := T.NodeDB.GetNode(nodeKey)
+	if err != nil {
+		return
+	}
+
+	if node.IsEnd {
+		_, winner := T.Game.SetState(node.State, node.Player)
+		value, isSolved = resultFor(node.Player, winner), true
+	} else if node.Actions != nil {
+		// All children are solved, even after a proven win, so that every solved node is counted
+		allSolved, won := node.NHiddenActions == 0, false
+		value = math.Inf(-1)
+		for _, a := range node.Actions {
+			var childValue float64
+			var childSolved bool
+			if childValue, childSolved, err = T.solveNode(a.ActionNodeKey, solved, limit); err != nil {
+				return
+			}
+			if !childSolved {
+				allSolved = false
+				continue
+			}
+
+			// Values are for the player in turn of the child
+			if solved[string(a.ActionNodeKey)].player != node.Player {
+				childValue = 1 - childValue
+			}
+			value = math.Max(value, childValue)
+			won = won || childValue == 1
+		}
+		if isSolved = allSolved || won; !isSolved {
+			value = 0
+		}
+	}
+
+	solved[string(nodeKey)] = solvedValue{value: value, player: node.Player, solved: isSolved}
+
+	return
+}
@@ -0,0 +1,252 @@
+package mcts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rounds modes, i.e. how max rounds given when learning is to be interpreted for a continued tree
+const (
+	ContinueRounds int = iota
+	TotalRounds
+)
+
+// learnerStateVersion - Version of the learner state file format. Version 1 kept the number of random values drawn
+// rather than the state of the random source, a tree continued from it draws random values anew from its seed.
+const learnerStateVersion = 2
+
+// LearnerState - Everything needed to continue learning a tree as if it never was interrupted
+type LearnerState struct {
+	Version          int               `json:"version"`
+	PlayerA          string            `json:"playerA"`
+	PlayerB          string            `json:"playerB"`
+	NNodes           int64             `json:"nNodes"`
+	NReusedNodes     int64             `json:"nReusedNodes"`
+	NUnexpandedNodes int64             `json:"nUnexpandedNodes"`
+	Rounds           float64           `json:"rounds"`
+	MaxRounds        float64           `json:"maxRounds"`
+	OverlearnRounds  float64           `json:"overlearnRounds"`
+	DepthStats       map[int]int64     `json:"depthStats"`
+	Seed             int64             `json:"seed"`
+	RandomState      []uint64          `json:"randomState,omitempty"`
+	Config           TreeConfig        `json:"config"`
+	Playout          PlayoutStatistics `json:"playout"`
+}
+
+// TreeConfig - Tuning parameters the tree was learned with
+type TreeConfig struct {
+	UCTConstant          float64 `json:"uctConstant"`
+	PUCTConstant         float64 `json:"puctConstant"`
+	RandomRoundThreshold float32 `json:"randomRoundThreshold"`
+	OverlearnFactor      float64 `json:"overlearnFactor"`
+	WideningFactor       float64 `json:"wideningFactor"`
+	WideningExponent     float64 `json:"wideningExponent"`
+	DirichletAlpha       float64 `json:"dirichletAlpha"`
+	DirichletEpsilon     float64 `json:"dirichletEpsilon"`
+}
+
+// setDefaultConfig - Sets tuning parameters from configuration, used unless a state file tells otherwise
+func (T *Tree) setDefaultConfig() {
+	T.UCTConstant = conf.UCTConstant
+	T.PUCTConstant = conf.PUCTConstant
+	T.RandomRoundThreshold = conf.RandomRoundThreshold
+	T.DirichletAlpha = conf.DirichletAlpha
+	T.DirichletEpsilon = conf.DirichletEpsilon
+}
+
+// config - Returns the tuning parameters of the tree
+func (T *Tree) config() TreeConfig {
+	return TreeConfig{
+		UCTConstant:          T.UCTConstant,
+		PUCTConstant:         T.PUCTConstant,
+		RandomRoundThreshold: T.RandomRoundThreshold,
+		OverlearnFactor:      T.OverlearnFactor,
+		WideningFactor:       T.WideningFactor,
+		WideningExponent:     T.WideningExponent,
+		DirichletAlpha:       T.DirichletAlpha,
+		DirichletEpsilon:     T.DirichletEpsilon,
+	}
+}
+
+// setConfig - Sets the tuning parameters of the tree
+func (T *Tree) setConfig(config TreeConfig) {
+	T.UCTConstant = config.UCTConstant
+	T.PUCTConstant = config.PUCTConstant
+	T.RandomRoundThreshold = config.RandomRoundThreshold
+	T.OverlearnFactor = config.OverlearnFactor
+	T.WideningFactor = config.WideningFactor
+	T.WideningExponent = config.WideningExponent
+	T.DirichletAlpha = config.DirichletAlpha
+	T.DirichletEpsilon = config.DirichletEpsilon
+}
+
+// SaveState - Saves the current state of the tree for use if we want to continue to learn later. The state is
+// written to a temporary file that replaces the state file, so an interrupted save never leaves a broken state.
+func (T *Tree) SaveState() error {
+	// Only a tree drawing from its seeded source has a state of the source to save
+	if T.source != nil {
+		T.randomState = T.source.state()
+	}
+
+	state := LearnerState{
+		Version:          learnerStateVersion,
+		PlayerA:          T.PlayerA,
+		PlayerB:          T.PlayerB,
+		NNodes:           T.NNodes,
+		NReusedNodes:     T.NReusedNodes,
+		NUnexpandedNodes: T.NUnexpandedNodes,
+		Rounds:           T.Rounds,
+		MaxRounds:        T.MaxRounds,
+		OverlearnRounds:  T.OverlearnRounds,
+		DepthStats:       T.DepthStats,
+		Seed:             T.Seed,
+		RandomState:      T.randomState,
+		Config:           T.config(),
+	}
+
+	if keeper, ok := T.PlayoutPolicy.(statisticsKeeper); ok {
+		keeper.saveStatistics(&state.Playout)
+	}
+
+	buf, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		fmt.Printf("Error while encoding state: %s\n", err)
+		return err
+	}
+
+	tmpFilename := T.StateFilename + ".tmp"
+	if err = os.WriteFile(tmpFilename, append(buf, '\n'), 0644); err != nil {
+		fmt.Printf("Error while writing state to %s, %s\n", tmpFilename, err)
+		return err
+	}
+
+	if err = os.Rename(tmpFilename, T.StateFilename); err != nil {
+		fmt.Printf("Error while replacing %s, %s\n", T.StateFilename, err)
+		return err
+	}
+
+	return nil
+}
+
+// ReadAndSetState - Reads the state file, if any, and sets the state of the tree from it. Both the JSON learner
+// state and the legacy single line of comma separated values are understood.
+func (T *Tree) ReadAndSetState() error {
+	// If there is no state file we just have to go with defaults
+	if _, err := os.Stat(T.StateFilename); err != nil {
+		return nil
+	}
+
+	buf, err := os.ReadFile(T.StateFilename)
+	if err != nil {
+		fmt.Printf("Error while reading %s, %s\n", T.StateFilename, err)
+		return err
+	}
+
+	var state LearnerState
+	if bytes.HasPrefix(bytes.TrimSpace(buf), []byte("{")) {
+		if err = json.Unmarshal(buf, &state); err != nil {
+			fmt.Printf("Error while decoding state: %s\n", err)
+			return err
+		}
+		if state.Version > learnerStateVersion {
+			fmt.Printf("Error, state file version %d is newer than supported version %d\n", state.Version, learnerStateVersion)
+			return fmt.Errorf("error, state file version %d is newer than supported version %d", state.Version, learnerStateVersion)
+		}
+	} else {
+		if state, err = T.parseLegacyState(string(buf)); err != nil {
+			return err
+		}
+	}
+
+	T.PlayerA = state.PlayerA
+	T.PlayerB = state.PlayerB
+	T.NNodes = state.NNodes
+	T.NReusedNodes = state.NReusedNodes
+	T.NUnexpandedNodes = state.NUnexpandedNodes
+	T.Rounds = state.Rounds
+	T.MaxRounds = state.MaxRounds
+	T.OverlearnRounds = state.OverlearnRounds
+	T.setConfig(state.Config)
+	T.playoutStatistics = state.Playout
+
+	// Legacy states have no seed and no depth statistics, keep what the tree was created with
+	if state.Seed != 0 {
+		T.Seed = state.Seed
+		T.randomState = state.RandomState
+	}
+	if state.DepthStats != nil {
+		T.DepthStats = state.DepthStats
+	} else if T.DepthStats == nil {
+		T.DepthStats = make(map[int]int64)
+	}
+
+	T.Game.SetPlayers([2]string{state.PlayerA, state.PlayerB})
+
+	return nil
+}
+
+// parseLegacyState - Parses a state saved as a single line of comma separated players and counters, the tuning
+// parameters are taken as the current ones of the tree
+func (T *Tree) parseLegacyState(line string) (state LearnerState, err error) {
+	states := strings.Split(strings.TrimSpace(line), ",")
+	if len(states) < 7 {
+		fmt.Printf("Error, malformed state file %s\n", T.StateFilename)
+		return state, fmt.Errorf("error, malformed state file %s", T.StateFilename)
+	}
+
+	nNodes, err := strconv.Atoi(states[2])
+	if err != nil {
+		fmt.Printf("Error while reading number of nodes: %s\n", err)
+		return
+	}
+	rounds, err := strconv.Atoi(states[3])
+	if err != nil {
+		fmt.Printf("Error while reading rounds: %s\n", err)
+		return
+	}
+	overlearnRounds, err := strconv.Atoi(states[4])
+	if err != nil {
+		fmt.Printf("Error while reading overlearnRounds: %s\n", err)
+		return
+	}
+	nReusedNodes, err := strconv.Atoi(states[5])
+	if err != nil {
+		fmt.Printf("Error while reading number of reused nodes: %s\n", err)
+		return
+	}
+	nUnexpandedNodes, err := strconv.Atoi(states[6])
+	if err != nil {
+		fmt.Printf("Error while reading number of unexpanded nodes: %s\n", err)
+		return
+	}
+
+	state = LearnerState{
+		PlayerA:          states[0],
+		PlayerB:          states[1],
+		NNodes:           int64(nNodes),
+		NReusedNodes:     int64(nReusedNodes),
+		NUnexpandedNodes: int64(nUnexpandedNodes),
+		Rounds:           float64(rounds),
+		MaxRounds:        float64(rounds),
+		OverlearnRounds:  float64(overlearnRounds),
+		Config:           T.config(),
+	}
+
+	return
+}
+
+// SetPlayoutPolicy - Sets the playout policy used in simulations. A policy learning from results gets back what it
+// had learned when the state of the tree was saved, so that continued learning plays out the same as if it never
+// was interrupted.
+func (T *Tree) SetPlayoutPolicy(policy PlayoutPolicy) {
+	if keeper, ok := policy.(statisticsKeeper); ok {
+		keeper.restoreStatistics(T.playoutStatistics)
+	}
+
+	T.PlayoutPolicy = policy
+}
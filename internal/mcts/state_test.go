@@ -0,0 +1,165 @@
+package mcts
+
+import (
+	"bytes"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"github.com/gostonefire/go-mcts-v3/internal/tictactoe"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveAndReadState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "test.state")
+
+	tree := &Tree{
+		Game:             tictactoe.NewTicTacToe(3, "X", "Y"),
+		PlayerA:          "X",
+		PlayerB:          "Y",
+		NNodes:           10,
+		NReusedNodes:     2,
+		NUnexpandedNodes: 7,
+		DepthStats:       map[int]int64{1: 1, 2: 9},
+		Rounds:           500,
+		MaxRounds:        1000,
+		OverlearnFactor:  100,
+		Seed:             7,
+		StateFilename:    stateFile,
+	}
+	tree.setDefaultConfig()
+
+	// Let the policy learn from a few games so that there are both replies and MAST statistics to save
+	mast := NewMAST(1, rand.New(rand.NewSource(1)))
+	policy := NewLGRF2(mast)
+	games := [][]PlayedAction{
+		{{"X", Action{X: 1, Y: 1}}, {"Y", Action{X: 0, Y: 0}}, {"X", Action{X: 2, Y: 2}}},
+		{{"X", Action{X: 0, Y: 1}}, {"Y", Action{X: 1, Y: 1}}, {"X", Action{X: 0, Y: 0}}, {"Y", Action{X: 2, Y: 1}}},
+	}
+	policy.Update(games[0], "X")
+	policy.Update(games[1], "Y")
+	policy.Update(games[0], "")
+	tree.PlayoutPolicy = policy
+
+	if err := tree.SaveState(); err != nil {
+		t.Fatalf("failed to save state: %s", err)
+	}
+
+	other := &Tree{Game: tictactoe.NewTicTacToe(3, "X", "Y"), StateFilename: stateFile}
+	if err := other.ReadAndSetState(); err != nil {
+		t.Fatalf("failed to read state: %s", err)
+	}
+
+	if other.PlayerA != "X" || other.PlayerB != "Y" {
+		t.Errorf("expected players X and Y, got %s and %s", other.PlayerA, other.PlayerB)
+	}
+	if other.NNodes != 10 || other.NReusedNodes != 2 || other.NUnexpandedNodes != 7 {
+		t.Errorf("expected node counts 10, 2 and 7, got %d, %d and %d", other.NNodes, other.NReusedNodes, other.NUnexpandedNodes)
+	}
+	if other.Rounds != 500 || other.MaxRounds != 1000 || other.Seed != 7 {
+		t.Errorf("expected rounds 500, max rounds 1000 and seed 7, got %.0f, %.0f and %d", other.Rounds, other.MaxRounds, other.Seed)
+	}
+	if !reflect.DeepEqual(other.DepthStats, tree.DepthStats) {
+		t.Errorf("expected depth statistics %v, got %v", tree.DepthStats, other.DepthStats)
+	}
+	if other.config() != tree.config() {
+		t.Errorf("expected config %+v, got %+v", tree.config(), other.config())
+	}
+
+	restoredMAST := NewMAST(1, rand.New(rand.NewSource(1)))
+	restored := NewLGRF2(restoredMAST)
+	other.SetPlayoutPolicy(restored)
+
+	if !reflect.DeepEqual(restored.replies1, policy.replies1) || !reflect.DeepEqual(restored.replies2, policy.replies2) {
+		t.Errorf("expected replies %v and %v, got %v and %v", policy.replies1, policy.replies2, restored.replies1, restored.replies2)
+	}
+	if !reflect.DeepEqual(restoredMAST.stats, mast.stats) {
+		t.Errorf("expected MAST statistics to be restored")
+	}
+}
+
+func TestContinuedLearning(t *testing.T) {
+	dir := t.TempDir()
+	uninterrupted := filepath.Join(dir, "uninterrupted")
+	continued := filepath.Join(dir, "continued")
+
+	learnTestTree(t, uninterrupted, 600, true)
+	learnTestTree(t, continued, 300, true)
+	learnTestTree(t, continued, 300, false)
+
+	for _, suffix := range []string{"-actions.bin", "-map.bin", "-ovfl.bin", ".state"} {
+		want, err := os.ReadFile(uninterrupted + suffix)
+		if err != nil {
+			t.Fatalf("failed to read file: %s", err)
+		}
+		got, err := os.ReadFile(continued + suffix)
+		if err != nil {
+			t.Fatalf("failed to read file: %s", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("expected %s of the continued tree to equal the one of the uninterrupted tree", suffix)
+		}
+	}
+}
+
+// learnTestTree - Learns a tic-tac-toe tree with the given name for the given number of rounds using LGRF-2 playouts
+// and saves its state, a new tree is created if asked for and otherwise the tree is continued
+func learnTestTree(t *testing.T, name string, rounds float64, newTree bool) {
+	game := tictactoe.NewTicTacToe(3, "X", "Y")
+	initialState, _ := game.GetState()
+
+	nodeDB, err := db.NewNodeTree(name, "X", "Y", initialState, 1000, newTree)
+	if err != nil {
+		t.Fatalf("failed to create node tree: %s", err)
+	}
+	defer nodeDB.Close()
+
+	tree := NewTree(game, nodeDB, nil, 0, nil, rounds, ContinueRounds, 42, name+".state", newTree)
+	if tree == nil {
+		t.Fatalf("failed to create tree")
+	}
+	policy, err := NewPlayoutPolicy(LGRF2Playout, 1, tree.Rand)
+	if err != nil {
+		t.Fatalf("failed to create playout policy: %s", err)
+	}
+	tree.SetPlayoutPolicy(policy)
+
+	for {
+		actions, err := tree.Select()
+		if err != nil {
+			t.Fatalf("failed to select: %s", err)
+		}
+		if actions == nil {
+			break
+		}
+
+		isEnd, winner := tree.PlayAction(actions[len(actions)-1])
+		result := tree.WinnerResult(winner)
+		if !isEnd {
+			if actions, err = tree.Expand(actions); err != nil {
+				t.Fatalf("failed to expand: %s", err)
+			}
+			isEnd, winner = tree.PlayAction(actions[len(actions)-1])
+			result = tree.WinnerResult(winner)
+			if !isEnd {
+				if result, err = tree.Simulate(actions); err != nil {
+					t.Fatalf("failed to simulate: %s", err)
+				}
+			}
+		}
+
+		if isEnd {
+			if err = tree.SetNodeIsEnd(actions[len(actions)-1]); err != nil {
+				t.Fatalf("failed to set node is end: %s", err)
+			}
+		}
+		if err = tree.BackPropagation(actions, result); err != nil {
+			t.Fatalf("failed to back propagate: %s", err)
+		}
+	}
+
+	if err = tree.SaveState(); err != nil {
+		t.Fatalf("failed to save state: %s", err)
+	}
+}
@@ -0,0 +1,252 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
+	"os"
+	"sort"
+)
+
+// TreeStats - Statistics over a stored node tree. Depth is the length of the shortest path from the top node at
+// depth 1, nodes not reachable from the top have no depth. Branching factors are over expanded nodes and include
+// hidden actions. Visits of a node are the sum of visits of the actions leading to it. The transposition ratio is
+// the share of exposed actions leading to a node that other actions lead to as well. A node is solved if it is an
+// end node or its value is proven from solved children.
+type TreeStats struct {
+	Name               string          `json:"name"`
+	Rounds             float64         `json:"rounds"`
+	Nodes              int64           `json:"nodes"`
+	ExpandedNodes      int64           `json:"expandedNodes"`
+	UnexpandedNodes    int64           `json:"unexpandedNodes"`
+	EndNodes           int64           `json:"endNodes"`
+	SolvedNodes        int64           `json:"solvedNodes"`
+	UnreachableNodes   int64           `json:"unreachableNodes"`
+	Actions            int64           `json:"actions"`
+	HiddenActions      int64           `json:"hiddenActions"`
+	Depths             []DepthStat     `json:"depths"`
+	AvgBranching       float64         `json:"avgBranching"`
+	MaxBranching       int             `json:"maxBranching"`
+	Visits             VisitQuantiles  `json:"visits"`
+	TranspositionRatio float64         `json:"transpositionRatio"`
+	MultiParentNodes   int64           `json:"multiParentNodes"`
+	EndRatio           float64         `json:"endRatio"`
+	SolvedRatio        float64         `json:"solvedRatio"`
+	RootSolved         bool            `json:"rootSolved"`
+	RootValue          float64         `json:"rootValue"`
+	Files              []FileSize      `json:"files"`
+	HashMap            db.HashMapStats `json:"hashMap"`
+}
+
+// DepthStat - Node counts at one depth
+type DepthStat struct {
+	Depth    int   `json:"depth"`
+	Nodes    int64 `json:"nodes"`
+	Expanded int64 `json:"expanded"`
+	End      int64 `json:"end"`
+}
+
+// VisitQuantiles - Distribution of node visits
+type VisitQuantiles struct {
+	Min    uint64  `json:"min"`
+	P25    uint64  `json:"p25"`
+	Median uint64  `json:"median"`
+	P75    uint64  `json:"p75"`
+	P90    uint64  `json:"p90"`
+	P99    uint64  `json:"p99"`
+	Max    uint64  `json:"max"`
+	Mean   float64 `json:"mean"`
+}
+
+// FileSize - Size in bytes of a file belonging to the tree
+type FileSize struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// nodeFlags - What is known about a node when collecting statistics
+type nodeFlags struct {
+	expanded bool
+	isEnd    bool
+}
+
+// Statistics - Collects statistics over the node tree by scanning all nodes, walking the tree from the top for
+// depths and proving values of solved nodes
+func (T *Tree) Statistics() (stats TreeStats, err error) {
+	nodeTree, ok := T.NodeDB.(*db.NodeTree)
+	if !ok {
+		fmt.Println("Error, statistics requires a file based node tree")
+		return stats, fmt.Errorf("error, statistics requires a file based node tree")
+	}
+	stats.Name = T.Name
+	stats.Rounds = T.Rounds
+
+	top, err := T.NodeDB.GetTopAction()
+	if err != nil {
+		return
+	}
+	incoming := map[string]*incomingVisits{string(top.ActionNodeKey): {visits: top.Visits, nParents: 1}}
+	flags := make(map[string]nodeFlags)
+
+	var exposed int64
+	err = T.NodeDB.IterateNodes(func(nodeKey []byte, node db.MCNode) error {
+		stats.Nodes++
+		flags[string(nodeKey)] = nodeFlags{expanded: node.Actions != nil, isEnd: node.IsEnd}
+
+		switch {
+		case node.IsEnd:
+			stats.EndNodes++
+		case node.Actions == nil:
+			stats.UnexpandedNodes++
+		}
+		if node.Actions == nil {
+			return nil
+		}
+
+		stats.ExpandedNodes++
+		nActions := len(node.Actions) + node.NHiddenActions
+		stats.Actions += int64(nActions)
+		stats.HiddenActions += int64(node.NHiddenActions)
+		if nActions > stats.MaxBranching {
+			stats.MaxBranching = nActions
+		}
+
+		for _, a := range node.Actions {
+			exposed++
+			in, seen := incoming[string(a.ActionNodeKey)]
+			if !seen {
+				in = &incomingVisits{}
+				incoming[string(a.ActionNodeKey)] = in
+			}
+			in.visits += a.Visits
+			in.nParents++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	if stats.ExpandedNodes > 0 {
+		stats.AvgBranching = float64(stats.Actions) / float64(stats.ExpandedNodes)
+	}
+	if stats.Nodes > 0 {
+		stats.EndRatio = float64(stats.EndNodes) / float64(stats.Nodes)
+	}
+
+	// Transpositions and visits of nodes that actions lead to
+	visits := make([]uint64, 0, len(incoming))
+	var transposed int64
+	for _, in := range incoming {
+		visits = append(visits, in.visits)
+		if in.nParents > 1 {
+			stats.MultiParentNodes++
+			transposed += int64(in.nParents)
+		}
+	}
+	if exposed > 0 {
+		stats.TranspositionRatio = float64(transposed) / float64(exposed)
+	}
+	stats.Visits = visitQuantiles(visits)
+
+	// Node counts per depth
+	depths, err := NodeDepths(T.NodeDB)
+	if err != nil {
+		return
+	}
+	stats.UnreachableNodes = stats.Nodes - int64(len(depths))
+	byDepth := make(map[int]*DepthStat)
+	for key, depth := range depths {
+		d, seen := byDepth[depth]
+		if !seen {
+			d = &DepthStat{Depth: depth}
+			byDepth[depth] = d
+		}
+		d.Nodes++
+		if flags[key].expanded {
+			d.Expanded++
+		}
+		if flags[key].isEnd {
+			d.End++
+		}
+	}
+	for _, d := range byDepth {
+		stats.Depths = append(stats.Depths, *d)
+	}
+	sort.Slice(stats.Depths, func(i, j int) bool { return stats.Depths[i].Depth < stats.Depths[j].Depth })
+
+	// Solved nodes
+	solved := make(map[string]solvedValue)
+	if stats.RootValue, stats.RootSolved, err = T.solveNode(top.ActionNodeKey, solved, 0); err != nil {
+		return
+	}
+	for _, s := range solved {
+		if s.solved {
+			stats.SolvedNodes++
+		}
+	}
+	if stats.Nodes > 0 {
+		stats.SolvedRatio = float64(stats.SolvedNodes) / float64(stats.Nodes)
+	}
+
+	if stats.HashMap, err = nodeTree.HashMapStats(); err != nil {
+		return
+	}
+	stats.Files = treeFileSizes(T.Name)
+
+	return
+}
+
+// visitQuantiles - Returns quantiles of visits, the slice is sorted in place
+func visitQuantiles(visits []uint64) (q VisitQuantiles) {
+	if len(visits) == 0 {
+		return
+	}
+	sort.Slice(visits, func(i, j int) bool { return visits[i] < visits[j] })
+
+	quantile := func(p float64) uint64 {
+		return visits[int(math.Round(p*float64(len(visits)-1)))]
+	}
+
+	var sum float64
+	for _, v := range visits {
+		sum += float64(v)
+	}
+
+	return VisitQuantiles{
+		Min:    visits[0],
+		P25:    quantile(0.25),
+		Median: quantile(0.5),
+		P75:    quantile(0.75),
+		P90:    quantile(0.9),
+		P99:    quantile(0.99),
+		Max:    visits[len(visits)-1],
+		Mean:   sum / float64(len(visits)),
+	}
+}
+
+// treeGrowthSize - Returns the total size of the files of the named tree that grow while learning, i.e. the
+// actions file and the overflow files of the node and AI DB hash maps. The map files are allocated in full when
+// created or resized and are left out.
+func treeGrowthSize(name string) (size int64) {
+	for _, suffix := range []string{"-actions.bin", "-ovfl.bin", "-aiDB-ovfl.bin"} {
+		if fileInfo, err := os.Stat(name + suffix); err == nil {
+			size += fileInfo.Size()
+		}
+	}
+
+	return
+}
+
+// treeFileSizes - Returns sizes of the files belonging to the named tree that exist
+func treeFileSizes(name string) (files []FileSize) {
+	for _, suffix := range []string{"-map.bin", "-ovfl.bin", "-actions.bin", ".state", "-aiDB-map.bin", "-aiDB-ovfl.bin", ".book", "-nn.bin"} {
+		fileName := name + suffix
+		if fileInfo, err := os.Stat(fileName); err == nil {
+			files = append(files, FileSize{Name: fileName, Size: fileInfo.Size()})
+		}
+	}
+
+	return
+}
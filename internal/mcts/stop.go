@@ -0,0 +1,126 @@
+package mcts
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+	"math"
+	"time"
+)
+
+// Stop criteria, i.e. what made learning stop
+const (
+	StopMaxRounds   = "max rounds reached"
+	StopTimeBudget  = "time budget spent"
+	StopNodeBudget  = "node budget reached"
+	StopDiskBudget  = "disk budget reached"
+	StopConvergence = "root converged"
+	StopSolved      = "root solved"
+	StopInterrupted = "interrupted"
+)
+
+// StopConditions - Conditions other than max rounds that stop learning, zero values disable them. The disk budget
+// is for the files that grow with the tree (see treeGrowthSize). Stable checks is the number of consecutive checks
+// the most visited action of the root and its value must be stable for the root to have converged.
+type StopConditions struct {
+	Deadline     time.Time
+	MaxNodes     int64
+	MaxDiskBytes int64
+	StableChecks int
+	StopIfSolved bool
+}
+
+// rootBest - The most visited action of the root and its value at a check of convergence
+type rootBest struct {
+	action Action
+	value  float64
+}
+
+// stopCriterion - Returns the stop criterion that is met given the top action, or an empty string if learning is to
+// continue. Max rounds and the node budget are checked every round while other conditions are checked at intervals.
+func (T *Tree) stopCriterion(top db.Action) (criterion string, err error) {
+	if T.Rounds >= T.MaxRounds {
+		return StopMaxRounds, nil
+	}
+	if T.Stop.MaxNodes > 0 && T.NNodes >= T.Stop.MaxNodes {
+		return StopNodeBudget, nil
+	}
+	if T.Rounds == 0 || math.Mod(T.Rounds, conf.StopCheckRounds) != 0 {
+		return
+	}
+
+	if !T.Stop.Deadline.IsZero() && time.Now().After(T.Stop.Deadline) {
+		return StopTimeBudget, nil
+	}
+
+	if T.Stop.MaxDiskBytes > 0 {
+		if treeGrowthSize(T.Name) >= T.Stop.MaxDiskBytes {
+			return StopDiskBudget, nil
+		}
+	}
+
+	if T.Stop.StableChecks > 0 && T.rootConverged(top) {
+		return StopConvergence, nil
+	}
+
+	if T.Stop.StopIfSolved && math.Mod(T.Rounds, conf.SolvedCheckRounds) == 0 {
+		var solved bool
+		if _, solved, err = T.solveNode(top.ActionNodeKey, make(map[string]solvedValue), conf.SolvedCheckNodeLimit); err != nil {
+			return
+		}
+		if solved {
+			return StopSolved, nil
+		}
+	}
+
+	return
+}
+
+// rootConverged - Records the most visited action of the root and its value, and returns true if they have been
+// stable over the required number of checks
+func (T *Tree) rootConverged(top db.Action) bool {
+	var best db.Action
+	for _, a := range top.ActionNode.Actions {
+		if a.Visits > best.Visits {
+			best = a
+		}
+	}
+	if best.Visits == 0 {
+		return false
+	}
+
+	T.rootHistory = append(T.rootHistory, rootBest{
+		action: Action{X: best.X, Y: best.Y, Pass: best.Pass},
+		value:  float64(best.Points) / 2 / float64(best.Visits),
+	})
+	if len(T.rootHistory) > T.Stop.StableChecks {
+		T.rootHistory = T.rootHistory[1:]
+	}
+	if len(T.rootHistory) < T.Stop.StableChecks {
+		return false
+	}
+
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, h := range T.rootHistory {
+		if h.action != T.rootHistory[0].action {
+			return false
+		}
+		minValue, maxValue = math.Min(minValue, h.value), math.Max(maxValue, h.value)
+	}
+
+	return maxValue-minValue <= conf.ConvergenceTolerance
+}
+
+// StopConditionsFromOptions - Returns stop conditions given budgets in minutes and megabytes, zero disables
+func StopConditionsFromOptions(timeBudget int, nodeBudget, diskBudget int64, stableChecks int, stopIfSolved bool) (stop StopConditions) {
+	stop = StopConditions{
+		MaxNodes:     nodeBudget,
+		MaxDiskBytes: diskBudget * 1024 * 1024,
+		StableChecks: stableChecks,
+		StopIfSolved: stopIfSolved,
+	}
+	if timeBudget > 0 {
+		stop.Deadline = time.Now().Add(time.Duration(timeBudget) * time.Minute)
+	}
+
+	return
+}
@@ -0,0 +1,92 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"os"
+	"strings"
+)
+
+// StrengthEvaluator - Evaluates the strength of a learning tree at intervals by playing its exploit policy against
+// a random and a heuristic baseline player, and logs the results as a learning curve in a CSV file with one row
+// per baseline player and evaluation
+type StrengthEvaluator struct {
+	Interval   int64
+	NGames     int
+	FileName   string
+	newGame    func() (BoardGame, error)
+	lastRounds float64
+}
+
+// NewStrengthEvaluator - Returns a new strength evaluator playing nGames games per baseline player every interval
+// rounds, each game on a new game instance given by newGame. Start rounds are the rounds of the tree when learning
+// starts, which were evaluated when learning before if a multiple of the interval and thus aren't evaluated again.
+func NewStrengthEvaluator(interval int64, nGames int, fileName string, newGame func() (BoardGame, error), startRounds float64) *StrengthEvaluator {
+	return &StrengthEvaluator{
+		Interval:   interval,
+		NGames:     nGames,
+		FileName:   fileName,
+		newGame:    newGame,
+		lastRounds: startRounds,
+	}
+}
+
+// EvaluateStrength - Evaluates the strength of the tree if it has a strength evaluator and rounds is a multiple of
+// its interval not already evaluated
+func (T *Tree) EvaluateStrength() (err error) {
+	S := T.StrengthEvaluator
+	if S == nil || T.Rounds == 0 || T.Rounds == S.lastRounds || int64(T.Rounds)%S.Interval != 0 {
+		return
+	}
+	S.lastRounds = T.Rounds
+
+	exploit := NewExploitPlayer(T)
+	results := make([]MatchResult, 0, 2)
+	for _, baseline := range []GamePlayer{NewRandomPlayer(), NewHeuristicPlayer()} {
+		var result MatchResult
+		if result, err = PlayMatch(S.newGame, [2]GamePlayer{exploit, baseline}, S.NGames, conf.EvalConcurrency); err != nil {
+			return
+		}
+		results = append(results, result)
+	}
+
+	summary := make([]string, len(results))
+	for i, r := range results {
+		summary[i] = fmt.Sprintf("vs %s %d/%d/%d (W/D/L) score %.3f", r.Players[1], r.Wins, r.Draws, r.Losses, r.Score())
+	}
+	fmt.Printf("Strength at %.0f rounds: %s\n", T.Rounds, strings.Join(summary, ", "))
+
+	return S.log(T.Rounds, T.NNodes, results)
+}
+
+// log - Appends results to the learning curve file, a header is written if the file is new
+func (S *StrengthEvaluator) log(rounds float64, nNodes int64, results []MatchResult) (err error) {
+	_, statErr := os.Stat(S.FileName)
+
+	f, err := os.OpenFile(S.FileName, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", S.FileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	if statErr != nil {
+		if _, err = fmt.Fprintln(f, "rounds,nodes,opponent,games,wins,draws,losses,win_rate,score"); err != nil {
+			fmt.Printf("Error while writing to %s, %s\n", S.FileName, err)
+			return
+		}
+	}
+
+	for _, r := range results {
+		// The win rate is zero rather than undefined if no games were played
+		winRate, _, _ := r.RateInterval(r.Wins)
+		_, err = fmt.Fprintf(f, "%.0f,%d,%s,%d,%d,%d,%d,%.4f,%.4f\n",
+			rounds, nNodes, r.Players[1], r.Games(), r.Wins, r.Draws, r.Losses, winRate, r.Score())
+		if err != nil {
+			fmt.Printf("Error while writing to %s, %s\n", S.FileName, err)
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,218 @@
+package mcts
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// confidenceZ - Standard score of the reported confidence intervals, 1.96 gives 95 %
+const confidenceZ float64 = 1.96
+
+// MatchResult - Wins, draws and losses of games between two players, counted for the first of the players
+type MatchResult struct {
+	Players [2]string
+	Wins    int
+	Draws   int
+	Losses  int
+}
+
+// Games - Returns number of played games
+func (M MatchResult) Games() int {
+	return M.Wins + M.Draws + M.Losses
+}
+
+// Score - Returns the mean score of the first player where a win is 1 and a draw 0.5
+func (M MatchResult) Score() float64 {
+	if M.Games() == 0 {
+		return 0.5
+	}
+
+	return (float64(M.Wins) + float64(M.Draws)/2) / float64(M.Games())
+}
+
+// ScoreInterval - Returns the confidence interval of the score using the normal approximation over the observed
+// game outcomes
+func (M MatchResult) ScoreInterval() (low, high float64) {
+	n := float64(M.Games())
+	score := M.Score()
+	if n == 0 {
+		return 0, 1
+	}
+
+	variance := (float64(M.Wins)*math.Pow(1-score, 2) +
+		float64(M.Draws)*math.Pow(0.5-score, 2) +
+		float64(M.Losses)*math.Pow(score, 2)) / n
+	margin := confidenceZ * math.Sqrt(variance/n)
+
+	return math.Max(0, score-margin), math.Min(1, score+margin)
+}
+
+// RateInterval - Returns the rate and its confidence interval (Wilson score interval) of count outcomes out of
+// the played games
+func (M MatchResult) RateInterval(count int) (rate, low, high float64) {
+	n := float64(M.Games())
+	if n == 0 {
+		return 0, 0, 1
+	}
+
+	rate = float64(count) / n
+	z2 := confidenceZ * confidenceZ
+	center := (rate + z2/(2*n)) / (1 + z2/n)
+	margin := confidenceZ / (1 + z2/n) * math.Sqrt(rate*(1-rate)/n+z2/(4*n*n))
+
+	return rate, math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// Elo - Returns the Elo difference of the first player over the second given the score, with its confidence
+// interval. A perfect score gives infinite differences.
+func (M MatchResult) Elo() (elo, low, high float64) {
+	scoreLow, scoreHigh := M.ScoreInterval()
+
+	return eloDifference(M.Score()), eloDifference(scoreLow), eloDifference(scoreHigh)
+}
+
+// eloDifference - Converts an expected score to an Elo difference
+func eloDifference(score float64) float64 {
+	if score <= 0 {
+		return math.Inf(-1)
+	} else if score >= 1 {
+		return math.Inf(1)
+	}
+
+	return -400 * math.Log10(1/score-1)
+}
+
+// gameOutcome - Outcome of one game for the first player of a match, 1 for a win, 0 for a loss and 0.5 for a draw
+type gameOutcome struct {
+	result float64
+	err    error
+}
+
+// PlayMatch - Plays nGames games between two players alternating which player starts, i.e. the first player
+// starts even games. Every game is played on its own game instance given by newGame, and at most concurrency
+// games are played at the same time.
+func PlayMatch(newGame func() (BoardGame, error), players [2]GamePlayer, nGames, concurrency int) (result MatchResult, err error) {
+	result.Players = [2]string{players[0].Name(), players[1].Name()}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	outcomes := make(chan gameOutcome, nGames)
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < nGames; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			var outcome gameOutcome
+			game, gameErr := newGame()
+			if gameErr != nil {
+				outcome.err = gameErr
+			} else {
+				outcome.result, outcome.err = playGame(game, [2]GamePlayer{players[i%2], players[1-i%2]})
+				if i%2 == 1 {
+					outcome.result = 1 - outcome.result
+				}
+			}
+			outcomes <- outcome
+		}(i)
+	}
+	wg.Wait()
+	close(outcomes)
+
+	for outcome := range outcomes {
+		if outcome.err != nil {
+			err = outcome.err
+			continue
+		}
+		switch outcome.result {
+		case 1:
+			result.Wins++
+		case 0:
+			result.Losses++
+		default:
+			result.Draws++
+		}
+	}
+	if err != nil {
+		fmt.Printf("Error while playing match: %s\n", err)
+	}
+
+	return
+}
+
+// playGame - Plays a game from the start position where the first of the players makes the first move.
+// It returns the result for the first player, 1 for a win, 0 for a loss and 0.5 for a draw.
+func playGame(game BoardGame, players [2]GamePlayer) (float64, error) {
+	game.Reset()
+	gamePlayers := game.GetPlayers()
+
+	for {
+		_, player := game.GetState()
+		gamePlayer := players[0]
+		if player != gamePlayers[0] {
+			gamePlayer = players[1]
+		}
+
+		action, err := gamePlayer.SelectAction(game)
+		if err != nil {
+			return 0, err
+		}
+
+		isDone, winner, err := game.Move(action.X, action.Y, action.Pass)
+		if err != nil {
+			return 0, fmt.Errorf("illegal move %s by %s: %s", FormatMove(action), gamePlayer.Name(), err)
+		}
+		if isDone {
+			return resultFor(gamePlayers[0], winner), nil
+		}
+	}
+}
+
+// FitRatings - Returns Elo ratings of all players given results of matches between them, rated relative to the
+// first player at zero. The ratings maximize the likelihood of the observed scores under the Elo model.
+func FitRatings(players []string, results []MatchResult) map[string]float64 {
+	ratings := make(map[string]float64, len(players))
+	for _, p := range players {
+		ratings[p] = 0
+	}
+
+	games := make(map[string]float64, len(players))
+	for _, r := range results {
+		games[r.Players[0]] += float64(r.Games())
+		games[r.Players[1]] += float64(r.Games())
+	}
+
+	// Gradient ascent where the step is scaled by the curvature at even scores, i.e. n * ln(10) / 400 * 0.25
+	for iteration := 0; iteration < 10000; iteration++ {
+		gradient := make(map[string]float64, len(players))
+		for _, r := range results {
+			n := float64(r.Games())
+			expected := 1 / (1 + math.Pow(10, (ratings[r.Players[1]]-ratings[r.Players[0]])/400))
+			gradient[r.Players[0]] += n * (r.Score() - expected)
+			gradient[r.Players[1]] -= n * (r.Score() - expected)
+		}
+
+		var maxStep float64
+		for _, p := range players[1:] {
+			if games[p] == 0 {
+				continue
+			}
+			// Keep ratings finite for players that never lost or never won
+			step := math.Max(-100, math.Min(100, 0.5*gradient[p]/(games[p]*math.Ln10/1600)))
+			ratings[p] = math.Max(-2000, math.Min(2000, ratings[p]+step))
+			maxStep = math.Max(maxStep, math.Abs(step))
+		}
+		if maxStep < 0.001 {
+			break
+		}
+	}
+
+	return ratings
+}
@@ -1,14 +1,12 @@
 package mcts
 
 import (
-	"bufio"
 	"fmt"
 	"github.com/gostonefire/go-mcts-v3/internal/conf"
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/book"
 	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
 	"math/rand"
 	"os"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -18,56 +16,88 @@ type BoardGame interface {
 	AvailableActions() ([][2]uint8, bool)                   // Returns: [X,Y] coordinates and Pass (with empty slice)
 	GetPlayers() [2]string                                  // Player names in start order
 	SetPlayers(players [2]string)                           // Player names in start order
+	BoardSize() (uint8, uint8)                              // Return: Number of columns (X) and rows (Y)
 	GetState() (string, string)                             // Return: State, Player in turn
 	SetState(state, playerInTurn string) (bool, string)     // Sets the game in a specific state, return as Move
 	PrintBoard()                                            // Prints the board on console
+	FormatPosition(state, playerInTurn string) string       // Return: Position in FEN like notation
+	ParsePosition(position string) (string, string, error)  // Return: State, Player in turn and error
 }
 
 type NodeDB interface {
-	AttachActionNodes(parentState, childPlayer string, actions []db.Action, actionResultStates []string) (attachedActions []db.Action, actionsAddress uint64, nReused int64, err error)
+	AttachActionNodes(parentState, childPlayer string, actions []db.Action, actionResultStates []string, nExposed int) (attachedActions []db.Action, actionsAddress uint64, nReused int64, err error)
+	ExposeNextAction(actionsAddress uint64) (action db.Action, reusedNode bool, err error)
 	GetTopAction() (action db.Action, err error)
 	GetNode(nodeKey []byte) (mcNode db.MCNode, err error)
 	UpdateActionStatistics(actionsAddress uint64, actionIndex uint64, newVisits, newPoints uint64) error
 	SetNodeIsEnd(nodeKey []byte) (err error)
+	IterateNodes(fn func(nodeKey []byte, mcNode db.MCNode) error) error
 }
 
 type AI interface {
-	RecordStateStatistics(player int, state string, visits uint64, points float64)
+	RecordStateStatistics(player int, state string, visits uint64, points float64) error
+	StateValue(player int, state string) (value float64, found bool)
 	WriteAndCloseBuffers() (err error)
 }
 
 // Action - Convenient structure for an action
 type Action struct {
-	X    uint8
-	Y    uint8
-	Pass bool
+	X    uint8 `json:"x"`
+	Y    uint8 `json:"y"`
+	Pass bool  `json:"pass"`
 }
 
 // Tree - Structure representing an MCTS tree
 type Tree struct {
-	Game             BoardGame
-	NodeDB           NodeDB
-	AI               AI
-	PlayerA          string
-	PlayerB          string
-	AtNode           db.MCNode
-	NNodes           int64
-	NReusedNodes     int64
-	NUnexpandedNodes int64
-	DepthStats       map[int]int64
-	Rounds           float64
-	MaxRounds        float64
-	OverlearnRounds  float64
-	OverlearnFactor  float64
-	StateFilename    string
+	Name                 string
+	Run                  string
+	Game                 BoardGame
+	NodeDB               NodeDB
+	AI                   AI
+	PlayerA              string
+	PlayerB              string
+	AtNode               db.MCNode
+	NNodes               int64
+	NReusedNodes         int64
+	NUnexpandedNodes     int64
+	DepthStats           map[int]int64
+	Rounds               float64
+	MaxRounds            float64
+	OverlearnRounds      float64
+	OverlearnFactor      float64
+	WideningFactor       float64
+	WideningExponent     float64
+	UCTConstant          float64
+	PUCTConstant         float64
+	DirichletAlpha       float64
+	DirichletEpsilon     float64
+	RandomRoundThreshold float32
+	Seed                 int64
+	Rand                 *rand.Rand
+	source               *xoshiroSource
+	randomState          []uint64
+	StateFilename        string
+	PlayoutPolicy        PlayoutPolicy
+	playoutStatistics    PlayoutStatistics
+	PlayoutCutoff        int
+	LeafEvaluator        Evaluator
+	PolicyModel          PolicyModel
+	Book                 *book.Book
+	Record               *GameRecord
+	StrengthEvaluator    *StrengthEvaluator
+	Stop                 StopConditions
+	StoppedBy            string
+	rootHistory          []rootBest
+	rootNoise            []float64
+	rootNoiseInterval    int64
+	playout              []PlayedAction
 }
 
-// NewTree - Returns a new tree with a single node at the top
-func NewTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, maxRounds float64, stateFilename string, forceNew bool) *Tree {
-	// Seed random generator
-	time.Now().UnixNano()
-	rand.Seed(time.Now().UnixNano())
-
+// NewTree - Returns a new tree with a single node at the top, or the tree continued from its state file. Max rounds
+// is either the number of rounds to learn in this run or the total number of rounds depending on rounds mode. A new
+// tree draws random values seeded by the given seed, or by the clock if zero, while a continued tree continues the
+// sequence of random values it was started with. The tree uses a random playout policy until given another.
+func NewTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, playoutCutoff int, policyModel PolicyModel, maxRounds float64, roundsMode int, seed int64, stateFilename string, forceNew bool) *Tree {
 	// Get players from the game
 	players := game.GetPlayers()
 
@@ -81,9 +111,18 @@ func NewTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, maxRounds float64, stateF
 		NNodes:           1,
 		NUnexpandedNodes: 1,
 		DepthStats:       make(map[int]int64),
-		MaxRounds:        maxRounds,
 		OverlearnFactor:  conf.OverlearnFactor,
+		WideningFactor:   conf.WideningFactor,
+		WideningExponent: conf.WideningExponent,
 		StateFilename:    stateFilename,
+		PlayoutCutoff:    playoutCutoff,
+		PolicyModel:      policyModel,
+		Seed:             seed,
+	}
+	tree.setDefaultConfig()
+
+	if tree.Seed == 0 {
+		tree.Seed = time.Now().UnixNano()
 	}
 
 	tree.DepthStats[1] = 1
@@ -105,14 +144,33 @@ func NewTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, maxRounds float64, stateF
 		}
 	}
 
+	// Create the random generator, a continued tree continues from the saved state of its source
+	tree.source = newXoshiroSource(tree.Seed)
+	if tree.randomState != nil {
+		if err := tree.source.setState(tree.randomState); err != nil {
+			return nil
+		}
+	}
+	tree.Rand = rand.New(tree.source)
+	tree.PlayoutPolicy = NewRandomPolicy(tree.Rand)
+
+	switch roundsMode {
+	case ContinueRounds:
+		tree.MaxRounds = tree.Rounds + maxRounds
+	case TotalRounds:
+		tree.MaxRounds = maxRounds
+	default:
+		fmt.Println("No rounds mode corresponding to given mode number")
+		return nil
+	}
+
 	return &tree
 }
 
 // NewPlayTree - Returns a new tree with a single node at the top
 func NewPlayTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, stateFilename string) *Tree {
-	// Seed random generator
-	time.Now().UnixNano()
-	rand.Seed(time.Now().UnixNano())
+	// Random values in play need not be reproducible, and the seed of the tree is kept for its state file
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	// Get players from the game
 	players := game.GetPlayers()
@@ -128,7 +186,10 @@ func NewPlayTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, stateFilename string)
 		NUnexpandedNodes: 1,
 		OverlearnFactor:  conf.OverlearnFactor,
 		StateFilename:    stateFilename,
+		PlayoutPolicy:    NewRandomPolicy(rng),
+		Rand:             rng,
 	}
+	tree.setDefaultConfig()
 
 	err := tree.ReadAndSetState()
 	if err != nil {
@@ -138,98 +199,18 @@ func NewPlayTree(game BoardGame, nodeDb NodeDB, aiMgmt AI, stateFilename string)
 	return &tree
 }
 
-// WriteAndCloseAIBuffers - Ensures whatever may be left in AI buffer gets written to file
-func (T *Tree) WriteAndCloseAIBuffers() (err error) {
-	err = T.AI.WriteAndCloseBuffers()
-
-	return
-}
-
-// SaveState - Saves the current state of the tree for use if we want to continue to learn later
-func (T *Tree) SaveState() error {
-	f, err := os.OpenFile(T.StateFilename, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		fmt.Printf("Error while open or create %s, %s\n", T.StateFilename, err)
-		return err
+// opponent - Returns the opponent of the given player
+func (T *Tree) opponent(player string) string {
+	if player == T.PlayerA {
+		return T.PlayerB
 	}
-	defer func(f *os.File) { err = f.Close() }(f)
-
-	_, err = fmt.Fprintf(
-		f,
-		"%s,%s,%d,%.0f,%.0f,%d,%d\n",
-		T.PlayerA,
-		T.PlayerB,
-		T.NNodes,
-		T.Rounds,
-		T.OverlearnRounds,
-		T.NReusedNodes,
-		T.NUnexpandedNodes,
-	)
 
-	if err != nil {
-		fmt.Printf("Error while writing state to file")
-		return err
-	}
-
-	return nil
+	return T.PlayerA
 }
 
-func (T *Tree) ReadAndSetState() error {
-	// If there is no state file we just have to go with defaults
-	if _, err := os.Stat(T.StateFilename); err != nil {
-		return nil
-	}
-
-	f, err := os.OpenFile(T.StateFilename, os.O_RDONLY, 0644)
-	if err != nil {
-		fmt.Printf("Error while open %s, %s\n", T.StateFilename, err)
-		return err
-	}
-	defer func(f *os.File) { err = f.Close() }(f)
-
-	r := bufio.NewReader(f)
-	buf, _, err := r.ReadLine()
-	state := string(buf)
-	states := strings.Split(state, ",")
-
-	playerA := states[0]
-	playerB := states[1]
-	nNodes, err := strconv.Atoi(states[2])
-	if err != nil {
-		fmt.Printf("Error while reading number of nodes: %s", err)
-		return err
-	}
-	rounds, err := strconv.Atoi(states[3])
-	if err != nil {
-		fmt.Printf("Error while reading rounds: %s", err)
-		return err
-	}
-	overlearnRounds, err := strconv.Atoi(states[4])
-	if err != nil {
-		fmt.Printf("Error while reading overlearnRounds: %s", err)
-		return err
-	}
-	nReusedNodes, err := strconv.Atoi(states[5])
-	if err != nil {
-		fmt.Printf("Error while reading number of reused nodes: %s", err)
-		return err
-	}
-	nUnexpandedNodes, err := strconv.Atoi(states[6])
-	if err != nil {
-		fmt.Printf("Error while reading number of unexpanded nodes: %s", err)
-		return err
-	}
-
-	T.PlayerA = playerA
-	T.PlayerB = playerB
-	T.NNodes = int64(nNodes)
-	T.Rounds = float64(rounds)
-	T.MaxRounds += T.Rounds
-	T.OverlearnRounds = float64(overlearnRounds)
-	T.NReusedNodes = int64(nReusedNodes)
-	T.NUnexpandedNodes = int64(nUnexpandedNodes)
-
-	T.Game.SetPlayers([2]string{playerA, playerB})
+// WriteAndCloseAIBuffers - Ensures whatever may be left in AI buffer gets written to file
+func (T *Tree) WriteAndCloseAIBuffers() (err error) {
+	err = T.AI.WriteAndCloseBuffers()
 
-	return nil
+	return
 }
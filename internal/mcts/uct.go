@@ -5,8 +5,8 @@ import (
 	"math"
 )
 
-// uct - Returns the upper confidence bound for trees given a node
-func uct(parentVisits, nodeVisits, nodePoints uint64) (float64, error) {
+// uct - Returns the upper confidence bound for trees given a node and the exploration constant
+func uct(parentVisits, nodeVisits, nodePoints uint64, c float64) (float64, error) {
 	if nodeVisits == 0 {
 		return 0, fmt.Errorf("node has no Visits, would result in division by zero")
 	}
@@ -16,6 +16,17 @@ func uct(parentVisits, nodeVisits, nodePoints uint64) (float64, error) {
 	N := float64(parentVisits)
 
 	// uctValue := nodePoints/nodeVisits + math.Sqrt2*math.Sqrt(math.Log(parentVisits)/nodeVisits)
-	uctValue := w/n + 10*math.Sqrt(math.Log(N)/n)
+	uctValue := w/n + c*math.Sqrt(math.Log(N)/n)
 	return uctValue, nil
 }
+
+// puct - Returns the PUCT value (as used in AlphaZero) given a node and the prior probability of its action.
+// Nodes without visits get a neutral value so that the prior alone decides among them.
+func puct(parentVisits, nodeVisits, nodePoints uint64, prior, c float64) float64 {
+	q := 0.5
+	if nodeVisits > 0 {
+		q = float64(nodePoints) / 2 / float64(nodeVisits) // Half since we are using integers for points
+	}
+
+	return q + c*prior*math.Sqrt(float64(parentVisits))/float64(1+nodeVisits)
+}
@@ -0,0 +1,116 @@
+package mcts
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/mcts/db"
+)
+
+// WalkFunc - Function called for each node reached in a walk, the node is attached to the action leading to it
+type WalkFunc func(action db.Action, depth int) error
+
+// reachedNode - Depth of a node reachable from the top, and visits and points summed over all actions leading to it
+type reachedNode struct {
+	depth  int
+	visits uint64
+	points uint64
+}
+
+// WalkTree - Walks the node tree breadth first from the top. The function fn is called once for every node that is
+// reached through actions with at least minVisits visits and is at most maxDepth deep, zero maxDepth means no limit.
+// Depth is the one given by NodeDepths, i.e. the shortest path from the top node at depth 1 whatever the visits of
+// its actions. Since nodes can be reached through several actions, the action given to fn has the visits and
+// points summed over all actions leading to the node from nodes reachable from the top, while its other fields are
+// from the action the node was first reached through.
+func WalkTree(nodeDB NodeDB, minVisits uint64, maxDepth int, fn WalkFunc) error {
+	reached, err := reachableNodes(nodeDB)
+	if err != nil {
+		return err
+	}
+
+	action, err := nodeDB.GetTopAction()
+	if err != nil {
+		return err
+	}
+
+	walked := map[string]bool{string(action.ActionNodeKey): true}
+	level := []db.Action{action}
+	for len(level) > 0 {
+		var next []db.Action
+		for _, a := range level {
+			node := reached[string(a.ActionNodeKey)]
+			a.Visits, a.Points = node.visits, node.points
+			if err = fn(a, node.depth); err != nil {
+				return err
+			}
+
+			if maxDepth > 0 && node.depth >= maxDepth {
+				continue
+			}
+
+			for _, child := range a.ActionNode.Actions {
+				key := string(child.ActionNodeKey)
+				if child.Visits < minVisits || walked[key] || maxDepth > 0 && reached[key].depth > maxDepth {
+					continue
+				}
+				walked[key] = true
+
+				if child.ActionNode, err = nodeDB.GetNode(child.ActionNodeKey); err != nil {
+					return err
+				}
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return nil
+}
+
+// NodeDepths - Returns the depth of every node reachable from the top keyed by node key, where depth is the length
+// of the shortest path from the top node at depth 1. Hidden actions are not followed.
+func NodeDepths(nodeDB NodeDB) (depths map[string]int, err error) {
+	reached, err := reachableNodes(nodeDB)
+	if err != nil {
+		return
+	}
+
+	depths = make(map[string]int, len(reached))
+	for key, node := range reached {
+		depths[key] = node.depth
+	}
+
+	return
+}
+
+// reachableNodes - Returns every node reachable from the top keyed by node key, with its depth as given by
+// NodeDepths and the visits and points of all actions leading to it. Hidden actions are not followed.
+func reachableNodes(nodeDB NodeDB) (reached map[string]*reachedNode, err error) {
+	action, err := nodeDB.GetTopAction()
+	if err != nil {
+		return
+	}
+
+	reached = map[string]*reachedNode{string(action.ActionNodeKey): {depth: 1, visits: action.Visits, points: action.Points}}
+	level := []db.MCNode{action.ActionNode}
+	for depth := 2; len(level) > 0; depth++ {
+		var next []db.MCNode
+		for _, node := range level {
+			for _, a := range node.Actions {
+				if r, seen := reached[string(a.ActionNodeKey)]; seen {
+					r.visits += a.Visits
+					r.points += a.Points
+					continue
+				}
+				reached[string(a.ActionNodeKey)] = &reachedNode{depth: depth, visits: a.Visits, points: a.Points}
+
+				var child db.MCNode
+				if child, err = nodeDB.GetNode(a.ActionNodeKey); err != nil {
+					return
+				}
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return
+}
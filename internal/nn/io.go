@@ -0,0 +1,212 @@
+package nn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+/*
+	Weights file format, all numbers little endian:
+
+	Magic          4 bytes "MCNN"
+	Version        uint32 (currently 1)
+	NInput         uint32
+	NHidden        uint32, number of hidden layers
+	HiddenSizes    uint32 per hidden layer
+	NPolicy        uint32
+	Layers         hidden layers in order followed by the value layer (1 output) and the policy layer (NPolicy
+	               outputs), each as weights followed by biases in float32. Weights are stored in row-major order,
+	               i.e. all weights for the first output followed by all weights for the second output and so on.
+*/
+
+// fileMagic - Identifies a weights file
+const fileMagic string = "MCNN"
+
+// fileVersion - Version of the weights file format
+const fileVersion uint32 = 1
+
+// Save - Saves the network weights to file
+func (N *Network) Save(fileName string) (err error) {
+	f, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	w := bufio.NewWriter(f)
+
+	header := []uint32{fileVersion, uint32(N.NInput), uint32(len(N.hidden))}
+	for _, l := range N.hidden {
+		header = append(header, uint32(l.out))
+	}
+	header = append(header, uint32(N.NPolicy))
+
+	if _, err = w.WriteString(fileMagic); err != nil {
+		fmt.Printf("Error while writing weights file header: %s\n", err)
+		return
+	}
+	if err = binary.Write(w, binary.LittleEndian, header); err != nil {
+		fmt.Printf("Error while writing weights file header: %s\n", err)
+		return
+	}
+
+	for _, l := range append(N.hidden, N.value, N.policy) {
+		if err = writeFloats(w, l.weights); err != nil {
+			return
+		}
+		if err = writeFloats(w, l.biases); err != nil {
+			return
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		fmt.Printf("Error while writing weights to file: %s\n", err)
+	}
+
+	return
+}
+
+// Load - Loads a network from a weights file. The layer sizes in the header must be non-zero and account for the
+// length of the file exactly, so that a corrupt header can't make the network allocate more than the file holds.
+func Load(fileName string) (network *Network, err error) {
+	f, err := os.OpenFile(fileName, os.O_RDONLY, 0644)
+	if err != nil {
+		fmt.Printf("Error while open %s, %s\n", fileName, err)
+		return
+	}
+	defer func(f *os.File) { _ = f.Close() }(f)
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		fmt.Printf("Error while getting file info of %s, %s\n", fileName, err)
+		return
+	}
+	fileSize := uint64(fileInfo.Size())
+
+	r := bufio.NewReader(f)
+
+	magic := make([]byte, len(fileMagic))
+	if _, err = io.ReadFull(r, magic); err != nil || string(magic) != fileMagic {
+		fmt.Printf("Error, %s is not a weights file\n", fileName)
+		return nil, fmt.Errorf("error, %s is not a weights file", fileName)
+	}
+
+	var version, nInput, nHidden, nPolicy uint32
+	if err = binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return
+	}
+	if version != fileVersion {
+		fmt.Printf("Error, unsupported weights file version: %d\n", version)
+		return nil, fmt.Errorf("error, unsupported weights file version: %d", version)
+	}
+	if err = binary.Read(r, binary.LittleEndian, &nInput); err != nil {
+		return
+	}
+	if err = binary.Read(r, binary.LittleEndian, &nHidden); err != nil {
+		return
+	}
+
+	// Magic, version, number of inputs, number of hidden layers, hidden layer sizes and number of policy outputs
+	headerSize := uint64(len(fileMagic)) + 4*(4+uint64(nHidden))
+	if headerSize > fileSize {
+		fmt.Printf("Error, %s is truncated, %d hidden layers don't fit\n", fileName, nHidden)
+		return nil, fmt.Errorf("error, %s is truncated, %d hidden layers don't fit", fileName, nHidden)
+	}
+	hiddenSizes := make([]uint32, nHidden)
+	if err = binary.Read(r, binary.LittleEndian, hiddenSizes); err != nil {
+		return
+	}
+	if err = binary.Read(r, binary.LittleEndian, &nPolicy); err != nil {
+		return
+	}
+
+	if err = checkLayerSizes(fileName, fileSize-headerSize, nInput, hiddenSizes, nPolicy); err != nil {
+		return
+	}
+
+	sizes := make([]int, nHidden)
+	for i, h := range hiddenSizes {
+		sizes[i] = int(h)
+	}
+	network = newNetwork(int(nInput), sizes, int(nPolicy))
+
+	for _, l := range append(network.hidden, network.value, network.policy) {
+		if err = readFloats(r, l.weights); err != nil {
+			return nil, err
+		}
+		if err = readFloats(r, l.biases); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// checkLayerSizes - Checks that all layer sizes are non-zero and that the weights and biases of the layers take
+// exactly the given number of bytes of float32 values
+func checkLayerSizes(fileName string, nBytes uint64, nInput uint32, hiddenSizes []uint32, nPolicy uint32) error {
+	outputs := append(append([]uint32{}, hiddenSizes...), 1, nPolicy)
+	for _, size := range append([]uint32{nInput}, outputs...) {
+		if size == 0 {
+			fmt.Printf("Error, %s has a layer of size zero\n", fileName)
+			return fmt.Errorf("error, %s has a layer of size zero", fileName)
+		}
+	}
+
+	// Sizes are below 2^32 so the values of a layer never overflow, and summing stops once the file is exceeded
+	maxFloats := nBytes / 4
+	var nFloats uint64
+	in := uint64(nInput)
+	for i, out := range outputs {
+		layerFloats := in*uint64(out) + uint64(out)
+		if layerFloats > maxFloats-nFloats {
+			nFloats = maxFloats + 1
+			break
+		}
+		nFloats += layerFloats
+
+		// Both the value and the policy layer take the output of the last hidden layer
+		if i < len(hiddenSizes) {
+			in = uint64(out)
+		}
+	}
+
+	if nFloats*4 != nBytes {
+		fmt.Printf("Error, layer sizes of %s don't match its %d bytes of weights\n", fileName, nBytes)
+		return fmt.Errorf("error, layer sizes of %s don't match its %d bytes of weights", fileName, nBytes)
+	}
+
+	return nil
+}
+
+// writeFloats - Writes values as float32
+func writeFloats(w io.Writer, values []float64) (err error) {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(float32(v)))
+	}
+	if _, err = w.Write(buf); err != nil {
+		fmt.Printf("Error while writing weights to file: %s\n", err)
+	}
+
+	return
+}
+
+// readFloats - Reads float32 values into values
+func readFloats(r io.Reader, values []float64) (err error) {
+	buf := make([]byte, 4*len(values))
+	if _, err = io.ReadFull(r, buf); err != nil {
+		fmt.Printf("Error while reading weights from file: %s\n", err)
+		return
+	}
+	for i := range values {
+		values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:])))
+	}
+
+	return
+}
@@ -0,0 +1,79 @@
+package nn
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "test-nn.bin")
+	network := NewNetwork(9, []int{8, 4}, 9, rand.New(rand.NewSource(1)))
+	if err := network.Save(fileName); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	loaded, err := Load(fileName)
+	if err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if loaded.NInput != 9 || loaded.NPolicy != 9 || len(loaded.hidden) != 2 || loaded.hidden[0].out != 8 || loaded.hidden[1].out != 4 {
+		t.Fatalf("expected sizes 9, [8 4], 9, got %d, %v, %d", loaded.NInput, loaded.HiddenSizes(), loaded.NPolicy)
+	}
+
+	// Weights are stored as float32
+	original := append(network.hidden, network.value, network.policy)
+	for i, l := range append(loaded.hidden, loaded.value, loaded.policy) {
+		for j, w := range l.weights {
+			if w != float64(float32(original[i].weights[j])) {
+				t.Fatalf("layer %d weight %d: expected %v, got %v", i, j, float32(original[i].weights[j]), w)
+			}
+		}
+		for j, b := range l.biases {
+			if b != float64(float32(original[i].biases[j])) {
+				t.Fatalf("layer %d bias %d: expected %v, got %v", i, j, float32(original[i].biases[j]), b)
+			}
+		}
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "test-nn.bin")
+	if err := NewNetwork(9, []int{8}, 9, rand.New(rand.NewSource(1))).Save(fileName); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read: %s", err)
+	}
+
+	// Header fields after the magic: version, inputs, hidden layers, hidden size and policy outputs
+	corrupt := map[string]func([]byte) []byte{
+		"truncated": func(b []byte) []byte { return b[:len(b)-4] },
+		"extended":  func(b []byte) []byte { return append(b, 0, 0, 0, 0) },
+		"huge layers": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[12:], 1<<30)
+			return b
+		},
+		"larger hidden layer": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[16:], 1<<31)
+			return b
+		},
+		"zero inputs": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[8:], 0)
+			return b
+		},
+	}
+	for name, corruptFn := range corrupt {
+		corruptFile := filepath.Join(dir, "corrupt-nn.bin")
+		if err = os.WriteFile(corruptFile, corruptFn(append([]byte{}, content...)), 0644); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+		if _, err = Load(corruptFile); err == nil {
+			t.Errorf("expected error loading %s weights file", name)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+)
+
+// layer - A fully connected layer with weights in row-major order (one row of inputs per output)
+type layer struct {
+	in      int
+	out     int
+	weights []float64
+	biases  []float64
+}
+
+// Network - A small multi-layer perceptron with a shared trunk of ReLU layers and two heads, one value head giving
+// the probability that the player in turn wins and one policy head giving a probability per action
+type Network struct {
+	NInput  int
+	NPolicy int
+	hidden  []layer
+	value   layer
+	policy  layer
+}
+
+// Sample - One training sample, a nil Policy means the sample has no policy target
+type Sample struct {
+	Input  []float64
+	Value  float64
+	Policy []float64
+}
+
+// NewNetwork - Returns a new network with weights randomly initialized from rng
+func NewNetwork(nInput int, hiddenSizes []int, nPolicy int, rng *rand.Rand) *Network {
+	network := newNetwork(nInput, hiddenSizes, nPolicy)
+	for _, l := range append(network.hidden, network.value, network.policy) {
+		l.initWeights(rng)
+	}
+
+	return network
+}
+
+// newNetwork - Returns a new network with all weights and biases zero
+func newNetwork(nInput int, hiddenSizes []int, nPolicy int) *Network {
+	network := Network{
+		NInput:  nInput,
+		NPolicy: nPolicy,
+		hidden:  make([]layer, len(hiddenSizes)),
+	}
+
+	in := nInput
+	for i, h := range hiddenSizes {
+		network.hidden[i] = newLayer(in, h)
+		in = h
+	}
+	network.value = newLayer(in, 1)
+	network.policy = newLayer(in, nPolicy)
+
+	return &network
+}
+
+// newLayer - Returns a new layer with zero weights and biases
+func newLayer(in, out int) layer {
+	return layer{
+		in:      in,
+		out:     out,
+		weights: make([]float64, in*out),
+		biases:  make([]float64, out),
+	}
+}
+
+// initWeights - He initializes the weights of the layer from rng
+func (L *layer) initWeights(rng *rand.Rand) {
+	scale := math.Sqrt(2 / float64(L.in))
+	for i := range L.weights {
+		L.weights[i] = rng.NormFloat64() * scale
+	}
+}
+
+// HiddenSizes - Returns the number of units in each hidden layer
+func (N *Network) HiddenSizes() []int {
+	sizes := make([]int, len(N.hidden))
+	for i, l := range N.hidden {
+		sizes[i] = l.out
+	}
+
+	return sizes
+}
+
+// Predict - Returns the value (probability that the player in turn wins) and the policy for an input
+func (N *Network) Predict(input []float64) (value float64, policy []float64) {
+	activations := N.forwardTrunk(input)
+	trunk := activations[len(activations)-1]
+
+	value = sigmoid(N.value.forward(trunk)[0])
+	policy = softmax(N.policy.forward(trunk))
+
+	return
+}
+
+// Train - Trains the network on the samples using mini-batch stochastic gradient descent, shuffling the samples
+// every epoch using rng. It returns the average loss (value and policy cross entropy) over the last epoch.
+func (N *Network) Train(samples []Sample, epochs int, learningRate float64, batchSize int, rng *rand.Rand) (loss float64) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+
+	gradients := N.newGradients()
+	for e := 0; e < epochs; e++ {
+		loss = 0
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		for start := 0; start < len(order); start += batchSize {
+			end := start + batchSize
+			if end > len(order) {
+				end = len(order)
+			}
+
+			gradients.reset()
+			for _, o := range order[start:end] {
+				loss += N.backward(samples[o], gradients)
+			}
+			N.apply(gradients, learningRate/float64(end-start))
+		}
+
+		if len(samples) > 0 {
+			loss /= float64(len(samples))
+		}
+	}
+
+	return
+}
+
+// forwardTrunk - Returns the activations of the input and every hidden layer
+func (N *Network) forwardTrunk(input []float64) [][]float64 {
+	activations := make([][]float64, 1, len(N.hidden)+1)
+	activations[0] = input
+	for _, l := range N.hidden {
+		a := l.forward(activations[len(activations)-1])
+		for i := range a {
+			a[i] = math.Max(0, a[i])
+		}
+		activations = append(activations, a)
+	}
+
+	return activations
+}
+
+// backward - Adds the gradients for one sample and returns its loss
+func (N *Network) backward(sample Sample, gradients *gradients) (loss float64) {
+	activations := N.forwardTrunk(sample.Input)
+	trunk := activations[len(activations)-1]
+
+	// Value head with binary cross entropy loss, the gradient on the logit is then simply v - target
+	v := sigmoid(N.value.forward(trunk)[0])
+	loss -= sample.Value*math.Log(v+epsilon) + (1-sample.Value)*math.Log(1-v+epsilon)
+	delta := make([]float64, len(trunk))
+	N.value.backward(trunk, []float64{v - sample.Value}, &gradients.value, delta)
+
+	// Policy head with cross entropy loss, the gradient on the logits is then p - target
+	if sample.Policy != nil {
+		p := softmax(N.policy.forward(trunk))
+		dPolicy := make([]float64, len(p))
+		for i := range p {
+			loss -= sample.Policy[i] * math.Log(p[i]+epsilon)
+			dPolicy[i] = p[i] - sample.Policy[i]
+		}
+		N.policy.backward(trunk, dPolicy, &gradients.policy, delta)
+	}
+
+	// Back through the trunk
+	for i := len(N.hidden) - 1; i >= 0; i-- {
+		for j := range delta {
+			if activations[i+1][j] <= 0 {
+				delta[j] = 0
+			}
+		}
+		var next []float64
+		if i > 0 {
+			next = make([]float64, len(activations[i]))
+		}
+		N.hidden[i].backward(activations[i], delta, &gradients.hidden[i], next)
+		delta = next
+	}
+
+	return
+}
+
+// apply - Applies accumulated gradients scaled by rate
+func (N *Network) apply(gradients *gradients, rate float64) {
+	for i := range N.hidden {
+		N.hidden[i].apply(gradients.hidden[i], rate)
+	}
+	N.value.apply(gradients.value, rate)
+	N.policy.apply(gradients.policy, rate)
+}
+
+// forward - Returns the output of the layer before any activation function
+func (L *layer) forward(input []float64) []float64 {
+	output := make([]float64, L.out)
+	for o := 0; o < L.out; o++ {
+		sum := L.biases[o]
+		row := L.weights[o*L.in : (o+1)*L.in]
+		for i, x := range input {
+			sum += row[i] * x
+		}
+		output[o] = sum
+	}
+
+	return output
+}
+
+// backward - Adds gradients for the layer given its input and the gradient on its output, and adds the gradient
+// on its input to inputDelta unless that is nil
+func (L *layer) backward(input, outputDelta []float64, gradient *layer, inputDelta []float64) {
+	for o, d := range outputDelta {
+		if d == 0 {
+			continue
+		}
+		gradient.biases[o] += d
+		row := L.weights[o*L.in : (o+1)*L.in]
+		gradientRow := gradient.weights[o*L.in : (o+1)*L.in]
+		for i, x := range input {
+			gradientRow[i] += d * x
+			if inputDelta != nil {
+				inputDelta[i] += d * row[i]
+			}
+		}
+	}
+}
+
+// apply - Subtracts the gradient scaled by rate from the layer parameters
+func (L *layer) apply(gradient layer, rate float64) {
+	for i := range L.weights {
+		L.weights[i] -= rate * gradient.weights[i]
+	}
+	for i := range L.biases {
+		L.biases[i] -= rate * gradient.biases[i]
+	}
+}
+
+// gradients - Accumulated gradients with the same shape as the network
+type gradients struct {
+	hidden []layer
+	value  layer
+	policy layer
+}
+
+// newGradients - Returns zeroed gradients shaped as the network
+func (N *Network) newGradients() *gradients {
+	g := gradients{hidden: make([]layer, len(N.hidden))}
+	for i, l := range N.hidden {
+		g.hidden[i] = zeroLayer(l.in, l.out)
+	}
+	g.value = zeroLayer(N.value.in, N.value.out)
+	g.policy = zeroLayer(N.policy.in, N.policy.out)
+
+	return &g
+}
+
+// reset - Sets all gradients to zero
+func (G *gradients) reset() {
+	for _, l := range append(G.hidden, G.value, G.policy) {
+		for i := range l.weights {
+			l.weights[i] = 0
+		}
+		for i := range l.biases {
+			l.biases[i] = 0
+		}
+	}
+}
+
+// zeroLayer - Returns a layer with all parameters set to zero
+func zeroLayer(in, out int) layer {
+	return layer{in: in, out: out, weights: make([]float64, in*out), biases: make([]float64, out)}
+}
+
+// epsilon - Small number keeping logarithms away from zero
+const epsilon float64 = 1e-12
+
+// sigmoid - The logistic function
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// softmax - Converts logits to probabilities
+func softmax(logits []float64) []float64 {
+	maxLogit := math.Inf(-1)
+	for _, l := range logits {
+		maxLogit = math.Max(maxLogit, l)
+	}
+
+	var sum float64
+	p := make([]float64, len(logits))
+	for i, l := range logits {
+		p[i] = math.Exp(l - maxLogit)
+		sum += p[i]
+	}
+	for i := range p {
+		p[i] /= sum
+	}
+
+	return p
+}
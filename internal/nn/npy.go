@@ -0,0 +1,123 @@
+package nn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+/*
+	NPY file format version 1.0 as read by numpy.load, with little endian float32 data in C order:
+
+	Magic          6 bytes "\x93NUMPY"
+	Version        2 bytes, major 1 and minor 0
+	HeaderLength   uint16
+	Header         Python dict literal with descr, fortran_order and shape, padded with spaces and ended with a
+	               newline so that the data starts at a multiple of 64 bytes
+	Data           rows of float32
+*/
+
+// npyHeaderSize - Total size of magic, version, header length and header. It is fixed so that the header can be
+// rewritten with the final number of rows when closing the file.
+const npyHeaderSize int = 128
+
+// NpyWriter - Writes rows of float32 to an NPY file without knowing the number of rows beforehand
+type NpyWriter struct {
+	file     *os.File
+	writer   *bufio.Writer
+	rowShape []int
+	rowSize  int
+	nRows    int
+}
+
+// NewNpyWriter - Creates an NPY file where each row has the given shape, an empty shape gives one scalar per row
+func NewNpyWriter(fileName string, rowShape []int) (npyWriter *NpyWriter, err error) {
+	f, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		fmt.Printf("Error while open or create %s, %s\n", fileName, err)
+		return
+	}
+
+	rowSize := 1
+	for _, s := range rowShape {
+		rowSize *= s
+	}
+
+	npyWriter = &NpyWriter{
+		file:     f,
+		writer:   bufio.NewWriter(f),
+		rowShape: rowShape,
+		rowSize:  rowSize,
+	}
+
+	// Placeholder header until the number of rows is known
+	if _, err = npyWriter.writer.Write(npyWriter.header()); err != nil {
+		fmt.Printf("Error while writing NPY header: %s\n", err)
+		_ = f.Close()
+		return nil, err
+	}
+
+	return
+}
+
+// Write - Writes one row
+func (N *NpyWriter) Write(row []float64) (err error) {
+	if len(row) != N.rowSize {
+		fmt.Printf("Error, row size %d does not match NPY row size %d\n", len(row), N.rowSize)
+		return fmt.Errorf("error, row size %d does not match NPY row size %d", len(row), N.rowSize)
+	}
+
+	buf := make([]byte, 4*len(row))
+	for i, v := range row {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(float32(v)))
+	}
+	if _, err = N.writer.Write(buf); err != nil {
+		fmt.Printf("Error while writing NPY data: %s\n", err)
+		return
+	}
+	N.nRows++
+
+	return
+}
+
+// Close - Writes the final header and closes the file
+func (N *NpyWriter) Close() (err error) {
+	defer func(f *os.File) { _ = f.Close() }(N.file)
+
+	if err = N.writer.Flush(); err != nil {
+		fmt.Printf("Error while writing NPY data: %s\n", err)
+		return
+	}
+	if _, err = N.file.WriteAt(N.header(), 0); err != nil {
+		fmt.Printf("Error while writing NPY header: %s\n", err)
+	}
+
+	return
+}
+
+// header - Returns the header for the current number of rows
+func (N *NpyWriter) header() []byte {
+	shape := []string{fmt.Sprintf("%d", N.nRows)}
+	for _, s := range N.rowShape {
+		shape = append(shape, fmt.Sprintf("%d", s))
+	}
+	shapeString := strings.Join(shape, ", ")
+	if len(shape) == 1 {
+		shapeString += ","
+	}
+
+	dict := fmt.Sprintf("{'descr': '<f4', 'fortran_order': False, 'shape': (%s), }", shapeString)
+	preamble := 10
+	dict += strings.Repeat(" ", npyHeaderSize-preamble-len(dict)-1) + "\n"
+
+	buf := make([]byte, 0, npyHeaderSize)
+	buf = append(buf, "\x93NUMPY"...)
+	buf = append(buf, 1, 0)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(dict)))
+	buf = append(buf, dict...)
+
+	return buf
+}
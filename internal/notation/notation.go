@@ -0,0 +1,119 @@
+package notation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board - Size and markers of a board in FEN like position notation, the first marker is used for the first player
+// and the second marker for the second player
+type Board struct {
+	Columns int
+	Rows    int
+	MarkerA byte
+	MarkerB byte
+}
+
+// Format - Formats a state, with positions column by column from the bottom left corner given as 0 for empty, 1 for
+// the first player and 2 for the second player, in notation. Rows are given from top to bottom separated by slashes
+// with one marker per position (. for empty) and are followed by the marker of the player in turn.
+func (B Board) Format(state string, playerAInTurn bool) string {
+	// Fix length of state by left padding with zeros
+	if diff := B.Columns*B.Rows - len(state); diff > 0 {
+		state = strings.Repeat("0", diff) + state
+	}
+
+	lines := make([]string, 0, B.Rows)
+	for y := B.Rows - 1; y >= 0; y-- {
+		line := make([]byte, B.Columns)
+		for x := 0; x < B.Columns; x++ {
+			switch state[x*B.Rows+y] {
+			case '1':
+				line[x] = B.MarkerA
+			case '2':
+				line[x] = B.MarkerB
+			default:
+				line[x] = '.'
+			}
+		}
+		lines = append(lines, string(line))
+	}
+
+	side := B.MarkerB
+	if playerAInTurn {
+		side = B.MarkerA
+	}
+
+	return fmt.Sprintf("%s %c", strings.Join(lines, "/"), side)
+}
+
+// Parse - Parses a position in notation (see Format), case insensitive, to a state and whether the first player is
+// in turn. Only the form of the position is checked, not whether it can be reached in the game.
+func (B Board) Parse(position string) (state string, playerAInTurn bool, err error) {
+	fields := strings.Fields(strings.ToUpper(position))
+	if len(fields) != 2 || len(fields[1]) != 1 {
+		fmt.Printf("Error, malformed position: %s\n", position)
+		return "", false, fmt.Errorf("error, malformed position: %s", position)
+	}
+
+	lines := strings.Split(fields[0], "/")
+	if len(lines) != B.Rows {
+		fmt.Printf("Error, position must have %d rows: %s\n", B.Rows, position)
+		return "", false, fmt.Errorf("error, position must have %d rows: %s", B.Rows, position)
+	}
+
+	buf := make([]byte, B.Columns*B.Rows)
+	for i, line := range lines {
+		if len(line) != B.Columns {
+			fmt.Printf("Error, position rows must have %d positions: %s\n", B.Columns, position)
+			return "", false, fmt.Errorf("error, position rows must have %d positions: %s", B.Columns, position)
+		}
+
+		y := B.Rows - 1 - i
+		for x := 0; x < B.Columns; x++ {
+			switch line[x] {
+			case B.MarkerA:
+				buf[x*B.Rows+y] = '1'
+			case B.MarkerB:
+				buf[x*B.Rows+y] = '2'
+			case '.':
+				buf[x*B.Rows+y] = '0'
+			default:
+				fmt.Printf("Error, unknown marker in position: %c\n", line[x])
+				return "", false, fmt.Errorf("error, unknown marker in position: %c", line[x])
+			}
+		}
+	}
+
+	switch fields[1][0] {
+	case B.MarkerA:
+		playerAInTurn = true
+	case B.MarkerB:
+		playerAInTurn = false
+	default:
+		fmt.Printf("Error, unknown player in turn in position: %s\n", fields[1])
+		return "", false, fmt.Errorf("error, unknown player in turn in position: %s", fields[1])
+	}
+
+	return string(buf), playerAInTurn, nil
+}
+
+// CheckTurns - Checks that a state and the player in turn can be reached in a game where the players take turns
+// placing one marker each, starting with the first player, without passing or removing markers
+func CheckTurns(state string, playerAInTurn bool) error {
+	nA, nB := strings.Count(state, "1"), strings.Count(state, "2")
+	if (playerAInTurn && nA != nB) || (!playerAInTurn && nA != nB+1) {
+		fmt.Printf("Error, %d and %d markers can't be on board with %s player in turn\n", nA, nB, turnName(playerAInTurn))
+		return fmt.Errorf("error, %d and %d markers can't be on board with %s player in turn", nA, nB, turnName(playerAInTurn))
+	}
+
+	return nil
+}
+
+// turnName - Returns the ordinal of the player in turn
+func turnName(playerAInTurn bool) string {
+	if playerAInTurn {
+		return "first"
+	}
+	return "second"
+}
@@ -0,0 +1,134 @@
+package notation_test
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/notation"
+	"github.com/gostonefire/go-mcts-v3/internal/othello"
+	"github.com/gostonefire/go-mcts-v3/internal/tictactoe"
+	"github.com/gostonefire/go-mcts-v3/internal/verticalfourinarow"
+	"math/rand"
+	"testing"
+)
+
+// game - The parts of a board game used when testing position notation
+type game interface {
+	Reset()
+	Move(x uint8, y uint8, pass bool) (bool, string, error)
+	AvailableActions() ([][2]uint8, bool)
+	GetState() (string, string)
+	SetState(state, playerInTurn string) (bool, string)
+	FormatPosition(state, playerInTurn string) string
+	ParsePosition(position string) (string, string, error)
+}
+
+func TestFormat(t *testing.T) {
+	board := notation.Board{Columns: 3, Rows: 2, MarkerA: 'X', MarkerB: 'Y'}
+
+	// Positions are given column by column from the bottom left corner
+	if position := board.Format("120200", false); position != "YY./X.. Y" {
+		t.Errorf("expected position %s, got %s", "YY./X.. Y", position)
+	}
+	if position := board.Format("1", true); position != "..X/... X" {
+		t.Errorf("expected left padded position %s, got %s", "..X/... X", position)
+	}
+}
+
+func TestParse(t *testing.T) {
+	board := notation.Board{Columns: 3, Rows: 2, MarkerA: 'X', MarkerB: 'Y'}
+
+	state, playerAInTurn, err := board.Parse("yy./x.. Y")
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if state != "120200" || playerAInTurn {
+		t.Errorf("expected state 120200 with second player in turn, got %s, %t", state, playerAInTurn)
+	}
+
+	for _, position := range []string{"..Y/X..", "..Y/X../... X", "..Y/X. X", "..Y/X.Z X", "..Y/X.. Z", "..Y/X.. XY"} {
+		if _, _, err = board.Parse(position); err == nil {
+			t.Errorf("expected error for malformed position %s", position)
+		}
+	}
+}
+
+func TestCheckTurns(t *testing.T) {
+	for _, test := range []struct {
+		state         string
+		playerAInTurn bool
+		valid         bool
+	}{
+		{"000", true, true},
+		{"100", false, true},
+		{"120", true, true},
+		{"100", true, false},
+		{"120", false, false},
+		{"200", false, false},
+		{"110", false, false},
+	} {
+		if err := notation.CheckTurns(test.state, test.playerAInTurn); (err == nil) != test.valid {
+			t.Errorf("expected state %s with first player in turn %t to be valid %t", test.state, test.playerAInTurn, test.valid)
+		}
+	}
+}
+
+func TestGamesRoundTrip(t *testing.T) {
+	newOthello := func() game {
+		o, err := othello.NewOthello(4, "B", "W")
+		if err != nil {
+			t.Fatalf("failed to create game: %s", err)
+		}
+		return o
+	}
+
+	for name, newGame := range map[string]func() game{
+		"tictactoe":          func() game { return tictactoe.NewTicTacToe(3, "X", "Y") },
+		"othello":            newOthello,
+		"verticalfourinarow": func() game { return verticalfourinarow.NewVerticalFIR("B", "W") },
+	} {
+		testRoundTrip(t, name, newGame(), newGame())
+	}
+}
+
+// testRoundTrip - Plays random games and checks that every position is parsed back to the state and player in turn
+// it was formatted from, and that the parsed state can be set in another game instance
+func testRoundTrip(t *testing.T, name string, g, other game) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 50; n++ {
+		g.Reset()
+		for {
+			state, player := g.GetState()
+			position := g.FormatPosition(state, player)
+
+			parsedState, parsedPlayer, err := g.ParsePosition(position)
+			if err != nil {
+				t.Fatalf("%s: failed to parse %s: %s", name, position, err)
+			}
+			if parsedState != state || parsedPlayer != player {
+				t.Fatalf("%s: round trip of %s, %s gave %s, %s", name, state, player, parsedState, parsedPlayer)
+			}
+
+			other.SetState(parsedState, parsedPlayer)
+			if otherState, otherPlayer := other.GetState(); otherState != state || otherPlayer != player {
+				t.Fatalf("%s: set state from %s gave %s, %s", name, position, otherState, otherPlayer)
+			}
+
+			actions, pass := g.AvailableActions()
+			if len(actions) == 0 && !pass {
+				break
+			}
+
+			var isDone bool
+			if pass {
+				isDone, _, err = g.Move(0, 0, true)
+			} else {
+				a := actions[rnd.Intn(len(actions))]
+				isDone, _, err = g.Move(a[0], a[1], false)
+			}
+			if err != nil {
+				t.Fatalf("%s: failed to move: %s", name, err)
+			}
+			if isDone {
+				break
+			}
+		}
+	}
+}
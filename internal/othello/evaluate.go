@@ -0,0 +1,67 @@
+package othello
+
+import "math"
+
+// Weights of the parts of the static evaluation, they add up to one
+const (
+	parityWeight   float64 = 0.2
+	mobilityWeight float64 = 0.3
+	cornerWeight   float64 = 0.5
+)
+
+// steepness - Steepness of the logistic function converting a score in [-1, 1] to a probability
+const steepness float64 = 4
+
+// Evaluate - Returns an estimated probability that the player in turn wins, based on disc parity, mobility and
+// corners held, each scored relative to the opponent
+func (O *Othello) Evaluate() float64 {
+	opponent := O.playerA
+	if O.playerInTurn == O.playerA {
+		opponent = O.playerB
+	}
+
+	var ownDiscs, opponentDiscs, ownCorners, opponentCorners int
+	last := O.size - 1
+	for x := 0; x < O.size; x++ {
+		for y := 0; y < O.size; y++ {
+			isCorner := (x == 0 || x == last) && (y == 0 || y == last)
+			switch O.board[x][y] {
+			case O.playerInTurn:
+				ownDiscs++
+				if isCorner {
+					ownCorners++
+				}
+			case opponent:
+				opponentDiscs++
+				if isCorner {
+					opponentCorners++
+				}
+			}
+		}
+	}
+
+	score := parityWeight*relativeScore(ownDiscs, opponentDiscs) +
+		mobilityWeight*relativeScore(countMoves(O.legitPlayerMoves[O.playerInTurn]), countMoves(O.legitPlayerMoves[opponent])) +
+		cornerWeight*relativeScore(ownCorners, opponentCorners)
+
+	return 1 / (1 + math.Exp(-steepness*score))
+}
+
+// countMoves - Returns number of distinct moves among legit moves, a move may be legit in several directions
+func countMoves(legitMoves []legit) int {
+	moves := make(map[coords]bool)
+	for _, l := range legitMoves {
+		moves[l.move] = true
+	}
+
+	return len(moves)
+}
+
+// relativeScore - Returns (own - opponent) / (own + opponent), or zero if both are zero
+func relativeScore(own, opponent int) float64 {
+	if own+opponent == 0 {
+		return 0
+	}
+
+	return float64(own-opponent) / float64(own+opponent)
+}
@@ -2,6 +2,7 @@ package othello
 
 import (
 	"fmt"
+	"sort"
 )
 
 type column []string
@@ -136,6 +137,14 @@ func (O *Othello) AvailableActions() (legit [][2]uint8, pass bool) {
 		i++
 	}
 
+	// Sort row by row so that the order doesn't depend on map iteration, which would make learning irreproducible
+	sort.Slice(legit, func(i, j int) bool {
+		if legit[i][1] != legit[j][1] {
+			return legit[i][1] < legit[j][1]
+		}
+		return legit[i][0] < legit[j][0]
+	})
+
 	// If there were no legit moves, then the player has to pass
 	if len(legit) == 0 {
 		pass = true
@@ -155,6 +164,11 @@ func (O *Othello) SetPlayers(players [2]string) {
 	O.playerB = players[1]
 }
 
+// BoardSize - Returns the number of columns (x) and rows (y) of the board
+func (O *Othello) BoardSize() (uint8, uint8) {
+	return uint8(O.size), uint8(O.size)
+}
+
 // GetState - Gets the state of the game as a base3 number formatted as a string and the player in turn
 func (O *Othello) GetState() (string, string) {
 	buf := make([]byte, O.size*O.size)
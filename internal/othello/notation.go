@@ -0,0 +1,51 @@
+package othello
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/notation"
+)
+
+// Markers used in position notation for the first and the second player
+const (
+	markerA byte = 'B'
+	markerB byte = 'W'
+)
+
+// notationBoard - Returns the board of the game in position notation
+func (O *Othello) notationBoard() notation.Board {
+	return notation.Board{Columns: O.size, Rows: O.size, MarkerA: markerA, MarkerB: markerB}
+}
+
+// FormatPosition - Formats a state and the player in turn in FEN like notation, i.e. rows from top to bottom
+// separated by slashes with one character per position (B, W or . for empty) followed by the player in turn,
+// e.g. "..../.WB./.BW./.... B"
+func (O *Othello) FormatPosition(state, playerInTurn string) string {
+	return O.notationBoard().Format(state, playerInTurn == O.playerA)
+}
+
+// ParsePosition - Parses a position in FEN like notation (see FormatPosition), case insensitive, to a state and the
+// player in turn. Positions with an empty center position are refused, but since markers are flipped and players
+// may pass, whether other positions can be reached is not checked.
+func (O *Othello) ParsePosition(position string) (state, playerInTurn string, err error) {
+	state, playerAInTurn, err := O.notationBoard().Parse(position)
+	if err != nil {
+		return
+	}
+
+	// The four center positions are occupied from the start and are never emptied
+	for x := O.size/2 - 1; x <= O.size/2; x++ {
+		for y := O.size/2 - 1; y <= O.size/2; y++ {
+			if state[x*O.size+y] == '0' {
+				fmt.Printf("Error, empty center position in position: %s\n", position)
+				return "", "", fmt.Errorf("error, empty center position in position: %s", position)
+			}
+		}
+	}
+
+	playerInTurn = O.playerB
+	if playerAInTurn {
+		playerInTurn = O.playerA
+	}
+
+	return
+}
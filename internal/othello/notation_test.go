@@ -0,0 +1,38 @@
+package othello
+
+import "testing"
+
+func TestFormatPosition(t *testing.T) {
+	game := newTestOthello(t)
+
+	position := game.FormatPosition(game.GetState())
+	if position != "..../.WB./.BW./.... B" {
+		t.Errorf("expected start position %s, got %s", "..../.WB./.BW./.... B", position)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	game := newTestOthello(t)
+
+	// Any player may be in turn since players can pass
+	if _, player, err := game.ParsePosition("..../.WB./.BW./.... W"); err != nil || player != "W" {
+		t.Errorf("expected W in turn, got %s, %v", player, err)
+	}
+
+	// Malformed and impossible positions, the last with an empty center position
+	for _, position := range []string{"..../.WB./.BW. B", "..../.WB./.BW./... B", "..../.WB./.BW./.... X", "..../.W../.BW./.... B"} {
+		if _, _, err := game.ParsePosition(position); err == nil {
+			t.Errorf("expected error for position %s", position)
+		}
+	}
+}
+
+// newTestOthello - Returns a new 4x4 game of Othello
+func newTestOthello(t *testing.T) *Othello {
+	game, err := NewOthello(4, "B", "W")
+	if err != nil {
+		t.Fatalf("failed to create game: %s", err)
+	}
+
+	return game
+}
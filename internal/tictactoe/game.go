@@ -50,24 +50,26 @@ func (T *TicTacToe) Move(x uint8, y uint8, pass bool) (bool, string, error) {
 		return false, "", fmt.Errorf("illegal move, spot already occupied")
 	}
 
-	T.board[x][y] = T.playerInTurn
+	mover := T.playerInTurn
+	T.board[x][y] = mover
 	T.rounds++
 	draw := T.evaluateGame()
 
-	// Check if the game is over, a draw results in 0 points and a win gives 1 point
-	if draw {
-		return true, "", nil
-	} else if T.done {
-		return true, T.playerInTurn, nil
-	}
-
-	// Switch playerInTurn who is next in turn
+	// Switch playerInTurn who is next in turn, also when the game is over so the player in turn of a final state
+	// is always the opponent of the player that made the last move
 	if T.playerInTurn == T.playerA {
 		T.playerInTurn = T.playerB
 	} else {
 		T.playerInTurn = T.playerA
 	}
 
+	// Check if the game is over, a draw results in 0 points and a win gives 1 point
+	if draw {
+		return true, "", nil
+	} else if T.done {
+		return true, mover, nil
+	}
+
 	return false, "", nil
 }
 
@@ -88,7 +90,7 @@ func (T *TicTacToe) AvailableActions() ([][2]uint8, bool) {
 }
 
 // evaluateGame - Evaluates whether the game is finished in which case done is set to true.
-// It returns true if the game is a draw, otherwise the winner is who is denoted in playerInTurn.
+// It returns true if the game is a draw, otherwise the winner is the player that made the last move.
 func (T *TicTacToe) evaluateGame() bool {
 	// Check columns and rows
 	for a := uint8(0); a < T.size; a++ {
@@ -154,6 +156,11 @@ func (T *TicTacToe) SetPlayers(players [2]string) {
 	T.playerB = players[1]
 }
 
+// BoardSize - Returns the number of columns (x) and rows (y) of the board
+func (T *TicTacToe) BoardSize() (uint8, uint8) {
+	return T.size, T.size
+}
+
 // GetState - Gets the state of the game as a base3 number formatted as a string and the player in turn
 func (T *TicTacToe) GetState() (string, string) {
 	buf := make([]byte, T.size*T.size)
@@ -206,7 +213,11 @@ func (T *TicTacToe) SetState(state, playerInTurn string) (bool, string) {
 	if draw := T.evaluateGame(); draw {
 		return true, ""
 	} else if T.done {
-		return true, T.playerInTurn
+		// The winner made the last move, i.e. is the opponent of the player in turn
+		if T.playerInTurn == T.playerA {
+			return true, T.playerB
+		}
+		return true, T.playerA
 	}
 
 	return false, ""
@@ -0,0 +1,54 @@
+package tictactoe
+
+import "testing"
+
+func TestFinalMove(t *testing.T) {
+	game := NewTicTacToe(3, "X", "Y")
+
+	// X takes the first row while Y plays the second row
+	moves := [][2]uint8{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}}
+	var done bool
+	var winner string
+	for _, m := range moves {
+		var err error
+		if done, winner, err = game.Move(m[0], m[1], false); err != nil {
+			t.Fatalf("failed to move %v: %s", m, err)
+		}
+	}
+
+	if !done || winner != "X" {
+		t.Fatalf("expected X to win after the final move, got done %t and winner %s", done, winner)
+	}
+
+	state, player := game.GetState()
+	if player != "Y" {
+		t.Errorf("expected Y in turn after the final move, got %s", player)
+	}
+
+	other := NewTicTacToe(3, "X", "Y")
+	if done, winner = other.SetState(state, player); !done || winner != "X" {
+		t.Errorf("expected X to win the final state, got done %t and winner %s", done, winner)
+	}
+}
+
+func TestFinalMoveDraw(t *testing.T) {
+	game := NewTicTacToe(3, "X", "Y")
+
+	// X Y X / X Y Y / Y X X
+	moves := [][2]uint8{{0, 0}, {1, 0}, {2, 0}, {1, 1}, {0, 1}, {2, 1}, {1, 2}, {0, 2}, {2, 2}}
+	var done bool
+	var winner string
+	for _, m := range moves {
+		var err error
+		if done, winner, err = game.Move(m[0], m[1], false); err != nil {
+			t.Fatalf("failed to move %v: %s", m, err)
+		}
+	}
+
+	if !done || winner != "" {
+		t.Fatalf("expected a draw after the final move, got done %t and winner %s", done, winner)
+	}
+	if _, player := game.GetState(); player != "Y" {
+		t.Errorf("expected Y in turn after the final move, got %s", player)
+	}
+}
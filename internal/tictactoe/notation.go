@@ -0,0 +1,43 @@
+package tictactoe
+
+import (
+	"github.com/gostonefire/go-mcts-v3/internal/notation"
+)
+
+// Markers used in position notation for the first and the second player, matching the player names X and Y
+const (
+	markerA byte = 'X'
+	markerB byte = 'Y'
+)
+
+// notationBoard - Returns the board of the game in position notation
+func (T *TicTacToe) notationBoard() notation.Board {
+	return notation.Board{Columns: int(T.size), Rows: int(T.size), MarkerA: markerA, MarkerB: markerB}
+}
+
+// FormatPosition - Formats a state and the player in turn in FEN like notation, i.e. rows from top to bottom
+// separated by slashes with one character per position (X, Y or . for empty) followed by the player in turn,
+// e.g. ".../.../... X"
+func (T *TicTacToe) FormatPosition(state, playerInTurn string) string {
+	return T.notationBoard().Format(state, playerInTurn == T.playerA)
+}
+
+// ParsePosition - Parses a position in FEN like notation (see FormatPosition), case insensitive, to a state and the
+// player in turn. Positions where the number of markers doesn't match the player in turn are refused, whether the
+// game was already won before the last marker was placed is not checked.
+func (T *TicTacToe) ParsePosition(position string) (state, playerInTurn string, err error) {
+	state, playerAInTurn, err := T.notationBoard().Parse(position)
+	if err != nil {
+		return
+	}
+	if err = notation.CheckTurns(state, playerAInTurn); err != nil {
+		return "", "", err
+	}
+
+	playerInTurn = T.playerB
+	if playerAInTurn {
+		playerInTurn = T.playerA
+	}
+
+	return
+}
@@ -0,0 +1,27 @@
+package tictactoe
+
+import "testing"
+
+func TestFormatPosition(t *testing.T) {
+	game := NewTicTacToe(3, "X", "Y")
+
+	position := game.FormatPosition(game.GetState())
+	if position != ".../.../... X" {
+		t.Errorf("expected start position %s, got %s", ".../.../... X", position)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	game := NewTicTacToe(3, "X", "Y")
+
+	if _, player, err := game.ParsePosition(".../.x./... y"); err != nil || player != "Y" {
+		t.Errorf("expected Y in turn, got %s, %v", player, err)
+	}
+
+	// Malformed and impossible positions
+	for _, position := range []string{"..../.../... X", ".../.../... Z", ".../.O./... X", ".../.X./... X", ".../.Y./... Y", ".../XX./... Y"} {
+		if _, _, err := game.ParsePosition(position); err == nil {
+			t.Errorf("expected error for position %s", position)
+		}
+	}
+}
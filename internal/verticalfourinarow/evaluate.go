@@ -0,0 +1,113 @@
+package verticalfourinarow
+
+import "math"
+
+// twoInRowWeight - Weight of an open window with two markers relative to one with three markers
+const twoInRowWeight float64 = 0.2
+
+// immediateWinProbability - Probability given to a player in turn that can win on the move
+const immediateWinProbability float64 = 0.99
+
+// steepness - Steepness of the logistic function converting a score in [-1, 1] to a probability
+const steepness float64 = 3
+
+// directions - Directions of windows of four, right, up, up right and down right
+var directions = [4][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// Evaluate - Returns an estimated probability that the player in turn wins, based on threats. A player in turn
+// that can win on the move almost certainly wins, and so does the opponent if it has two such moves that can't
+// both be blocked. Otherwise open windows of four (windows with markers of only one player) with three and two
+// markers are counted for both players and scored relative to each other.
+func (V *VerticalFIR) Evaluate() float64 {
+	opponent := V.playerA
+	if V.playerInTurn == V.playerA {
+		opponent = V.playerB
+	}
+
+	if V.countWinningMoves(V.playerInTurn) > 0 {
+		return immediateWinProbability
+	}
+	if V.countWinningMoves(opponent) > 1 {
+		return 1 - immediateWinProbability
+	}
+
+	var own, other float64
+	for c := 0; c < V.columns; c++ {
+		for r := 0; r < V.rows; r++ {
+			for _, d := range directions {
+				endC, endR := c+3*d[0], r+3*d[1]
+				if endC >= V.columns || endR < 0 || endR >= V.rows {
+					continue
+				}
+
+				var nOwn, nOther int
+				for i := 0; i < 4; i++ {
+					switch V.board[c+i*d[0]][r+i*d[1]] {
+					case V.playerInTurn:
+						nOwn++
+					case opponent:
+						nOther++
+					}
+				}
+
+				if nOther == 0 {
+					own += windowScore(nOwn)
+				} else if nOwn == 0 {
+					other += windowScore(nOther)
+				}
+			}
+		}
+	}
+
+	if own+other == 0 {
+		return 0.5
+	}
+
+	return 1 / (1 + math.Exp(-steepness*(own-other)/(own+other)))
+}
+
+// windowScore - Returns the score of an open window given number of markers in it
+func windowScore(markers int) float64 {
+	switch markers {
+	case 3:
+		return 1
+	case 2:
+		return twoInRowWeight
+	default:
+		return 0
+	}
+}
+
+// countWinningMoves - Returns number of columns where the player would get four in a row by dropping a marker
+func (V *VerticalFIR) countWinningMoves(player string) (n int) {
+	for c := 0; c < V.columns; c++ {
+		r := 0
+		for r < V.rows && V.board[c][r] != " " {
+			r++
+		}
+		if r < V.rows && V.completesFour(c, r, player) {
+			n++
+		}
+	}
+
+	return
+}
+
+// completesFour - Returns whether a marker of the player at column c and row r would give four in a row
+func (V *VerticalFIR) completesFour(c, r int, player string) bool {
+	for _, d := range directions {
+		inRow := 1
+		for _, sign := range []int{1, -1} {
+			x, y := c+sign*d[0], r+sign*d[1]
+			for x >= 0 && x < V.columns && y >= 0 && y < V.rows && V.board[x][y] == player {
+				inRow++
+				x, y = x+sign*d[0], y+sign*d[1]
+			}
+		}
+		if inRow >= 4 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -60,24 +60,26 @@ func (V *VerticalFIR) Move(x uint8, y uint8, pass bool) (bool, string, error) {
 		return false, "", fmt.Errorf("illegal move, spot already occupied")
 	}
 
-	V.board[c][r] = V.playerInTurn
+	mover := V.playerInTurn
+	V.board[c][r] = mover
 	V.rounds++
 	draw := V.evaluateGame()
 
-	// Check if the game is over, a draw results in 0 points and a win gives 1 point
-	if draw {
-		return true, "", nil
-	} else if V.done {
-		return true, V.playerInTurn, nil
-	}
-
-	// Switch playerInTurn who is next in turn
+	// Switch playerInTurn who is next in turn, also when the game is over so the player in turn of a final state
+	// is always the opponent of the player that made the last move
 	if V.playerInTurn == V.playerA {
 		V.playerInTurn = V.playerB
 	} else {
 		V.playerInTurn = V.playerA
 	}
 
+	// Check if the game is over, a draw results in 0 points and a win gives 1 point
+	if draw {
+		return true, "", nil
+	} else if V.done {
+		return true, mover, nil
+	}
+
 	return false, "", nil
 }
 
@@ -96,7 +98,7 @@ func (V *VerticalFIR) AvailableActions() ([][2]uint8, bool) {
 }
 
 // evaluateGame - Evaluates whether the game is finished in which case done is set to true.
-// It returns true if the game is a draw, otherwise the winner is who is denoted in playerInTurn.
+// It returns true if the game is a draw, otherwise the winner is the player that made the last move.
 func (V *VerticalFIR) evaluateGame() bool {
 	// Check columns
 	for c := 0; c < V.columns; c++ {
@@ -222,6 +224,11 @@ func (V *VerticalFIR) SetPlayers(players [2]string) {
 	V.playerB = players[1]
 }
 
+// BoardSize - Returns the number of columns (x) and rows (y) of the board
+func (V *VerticalFIR) BoardSize() (uint8, uint8) {
+	return uint8(V.columns), uint8(V.rows)
+}
+
 // GetState - Gets the state of the game as a base3 number formatted as a string and the player in turn
 func (V *VerticalFIR) GetState() (string, string) {
 	buf := make([]byte, V.columns*V.rows)
@@ -274,7 +281,11 @@ func (V *VerticalFIR) SetState(state, playerInTurn string) (bool, string) {
 	if draw := V.evaluateGame(); draw {
 		return true, ""
 	} else if V.done {
-		return true, V.playerInTurn
+		// The winner made the last move, i.e. is the opponent of the player in turn
+		if V.playerInTurn == V.playerA {
+			return true, V.playerB
+		}
+		return true, V.playerA
 	}
 
 	return false, ""
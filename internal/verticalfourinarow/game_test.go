@@ -0,0 +1,54 @@
+package verticalfourinarow
+
+import "testing"
+
+func TestFinalMove(t *testing.T) {
+	game := NewVerticalFIR("A", "B")
+
+	// A stacks four in column 0 while B plays column 1
+	columns := []uint8{0, 1, 0, 1, 0, 1, 0}
+	var done bool
+	var winner string
+	for _, c := range columns {
+		var err error
+		if done, winner, err = game.Move(c, 0, false); err != nil {
+			t.Fatalf("failed to move in column %d: %s", c, err)
+		}
+	}
+
+	if !done || winner != "A" {
+		t.Fatalf("expected A to win after the final move, got done %t and winner %s", done, winner)
+	}
+
+	state, player := game.GetState()
+	if player != "B" {
+		t.Errorf("expected B in turn after the final move, got %s", player)
+	}
+
+	other := NewVerticalFIR("A", "B")
+	if done, winner = other.SetState(state, player); !done || winner != "A" {
+		t.Errorf("expected A to win the final state, got done %t and winner %s", done, winner)
+	}
+}
+
+func TestFinalMoveSecondPlayer(t *testing.T) {
+	game := NewVerticalFIR("A", "B")
+
+	// B gets four in a row on the bottom row
+	columns := []uint8{6, 0, 6, 1, 6, 2, 5, 3}
+	var done bool
+	var winner string
+	for _, c := range columns {
+		var err error
+		if done, winner, err = game.Move(c, 0, false); err != nil {
+			t.Fatalf("failed to move in column %d: %s", c, err)
+		}
+	}
+
+	if !done || winner != "B" {
+		t.Fatalf("expected B to win after the final move, got done %t and winner %s", done, winner)
+	}
+	if _, player := game.GetState(); player != "A" {
+		t.Errorf("expected A in turn after the final move, got %s", player)
+	}
+}
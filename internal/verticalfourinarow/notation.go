@@ -0,0 +1,54 @@
+package verticalfourinarow
+
+import (
+	"fmt"
+	"github.com/gostonefire/go-mcts-v3/internal/notation"
+)
+
+// Markers used in position notation for the first and the second player
+const (
+	markerA byte = 'B'
+	markerB byte = 'W'
+)
+
+// notationBoard - Returns the board of the game in position notation
+func (V *VerticalFIR) notationBoard() notation.Board {
+	return notation.Board{Columns: V.columns, Rows: V.rows, MarkerA: markerA, MarkerB: markerB}
+}
+
+// FormatPosition - Formats a state and the player in turn in FEN like notation, i.e. rows from top to bottom
+// separated by slashes with one character per position (B, W or . for empty) followed by the player in turn,
+// e.g. "......./......./......./......./......./...B... W"
+func (V *VerticalFIR) FormatPosition(state, playerInTurn string) string {
+	return V.notationBoard().Format(state, playerInTurn == V.playerA)
+}
+
+// ParsePosition - Parses a position in FEN like notation (see FormatPosition), case insensitive, to a state and the
+// player in turn. Positions with markers above empty positions or where the number of markers doesn't match the
+// player in turn are refused, whether the game was already won before the last marker was placed is not checked.
+func (V *VerticalFIR) ParsePosition(position string) (state, playerInTurn string, err error) {
+	state, playerAInTurn, err := V.notationBoard().Parse(position)
+	if err != nil {
+		return
+	}
+	if err = notation.CheckTurns(state, playerAInTurn); err != nil {
+		return "", "", err
+	}
+
+	// Markers are dropped in columns and can't float above empty positions
+	for x := 0; x < V.columns; x++ {
+		for y := 1; y < V.rows; y++ {
+			if state[x*V.rows+y] != '0' && state[x*V.rows+y-1] == '0' {
+				fmt.Printf("Error, marker above empty position in column %d: %s\n", x+1, position)
+				return "", "", fmt.Errorf("error, marker above empty position in column %d: %s", x+1, position)
+			}
+		}
+	}
+
+	playerInTurn = V.playerB
+	if playerAInTurn {
+		playerInTurn = V.playerA
+	}
+
+	return
+}
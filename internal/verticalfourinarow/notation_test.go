@@ -0,0 +1,33 @@
+package verticalfourinarow
+
+import "testing"
+
+func TestFormatPosition(t *testing.T) {
+	game := NewVerticalFIR("B", "W")
+
+	position := game.FormatPosition(game.GetState())
+	if position != "......./......./......./......./......./....... B" {
+		t.Errorf("expected start position %s, got %s", "......./......./......./......./......./....... B", position)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	game := NewVerticalFIR("B", "W")
+
+	if _, player, err := game.ParsePosition("......./......./......./......./...w.../...b... b"); err != nil || player != "B" {
+		t.Errorf("expected B in turn, got %s, %v", player, err)
+	}
+
+	// Malformed and impossible positions, the last with a floating marker
+	for _, position := range []string{
+		"......./......./......./......./....... B",
+		"......./......./......./......./......./...... B",
+		"......./......./......./......./......./....... ",
+		"......./......./......./......./......./...B... B",
+		"......./......./......./......./..B..../...W... B",
+	} {
+		if _, _, err := game.ParsePosition(position); err == nil {
+			t.Errorf("expected error for position %s", position)
+		}
+	}
+}